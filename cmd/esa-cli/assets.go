@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/shellme/esa-cli/internal/assets"
+)
+
+// downloadAssets 記事中の画像を assets ディレクトリにダウンロードし、ローカルのパスに書き換えた本文を返す
+// ダウンロードに失敗した画像は警告を表示して元のURLのまま残す
+func downloadAssets(number int, body, dir string) string {
+	store, err := assets.OpenWorkspace(http.DefaultClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %s の読み込みに失敗しました: %v\n", assets.ManifestFile, err)
+		return body
+	}
+
+	body, errs := store.Localize(context.Background(), number, body, dir)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "⚠️  画像のダウンロードに失敗しました: %v\n", err)
+	}
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %s の保存に失敗しました: %v\n", assets.ManifestFile, err)
+	}
+	return body
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/doctor"
+	"github.com/spf13/pflag"
+)
+
+func runConfig(cmd *pflag.FlagSet, showOrigin, jsonOutput bool) {
+	if len(cmd.Args()) < 1 {
+		fmt.Println("❌ サブコマンドを指定してください (path|show|get|set|unset|list|doctor)")
+		fmt.Println("💡 使用例: esa-cli config show --origin")
+		os.Exit(1)
+	}
+
+	switch cmd.Args()[0] {
+	case "path":
+		fmt.Println(config.Path())
+		for _, path := range config.CandidatePaths() {
+			status := "なし"
+			if _, err := os.Stat(path); err == nil {
+				status = "あり"
+			}
+			fmt.Fprintf(os.Stderr, "  候補: %s (%s)\n", path, status)
+		}
+	case "show":
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+			fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+			os.Exit(1)
+		}
+
+		values := []struct {
+			key   string
+			value string
+		}{
+			{"profile", cfg.ActiveProfile},
+			{"team_name", cfg.TeamName},
+			{"access_token", maskToken(cfg.AccessToken)},
+		}
+		for _, v := range values {
+			if showOrigin {
+				fmt.Printf("%-14s %-24s (%s)\n", v.key, v.value, cfg.Origin(v.key))
+			} else {
+				fmt.Printf("%-14s %s\n", v.key, v.value)
+			}
+		}
+	case "list":
+		values := config.ListDefaults()
+		if len(values) == 0 {
+			fmt.Println("📭 デフォルト値は設定されていません")
+			fmt.Printf("💡 使用できるキー: %s\n", strings.Join(config.DefaultKeys(), ", "))
+			return
+		}
+		for _, kv := range values {
+			fmt.Printf("%s=%s\n", kv[0], kv[1])
+		}
+	case "get":
+		if len(cmd.Args()) < 2 {
+			fmt.Println("❌ キーを指定してください")
+			fmt.Println("💡 使用例: esa-cli config get category")
+			os.Exit(1)
+		}
+		value, err := config.GetDefault(cmd.Args()[1])
+		if err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	case "set":
+		if len(cmd.Args()) < 3 {
+			fmt.Println("❌ キーと値を指定してください")
+			fmt.Println("💡 使用例: esa-cli config set category 開発/メモ")
+			os.Exit(1)
+		}
+		key, value := cmd.Args()[1], strings.Join(cmd.Args()[2:], " ")
+		if err := config.SetDefault(key, value); err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s を設定しました: %s\n", key, value)
+	case "unset":
+		if len(cmd.Args()) < 2 {
+			fmt.Println("❌ キーを指定してください")
+			fmt.Println("💡 使用例: esa-cli config unset category")
+			os.Exit(1)
+		}
+		if err := config.UnsetDefault(cmd.Args()[1]); err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s を削除しました\n", cmd.Args()[1])
+	case "doctor":
+		runDoctor(jsonOutput)
+	default:
+		fmt.Printf("❌ 不明なサブコマンド: %s\n", cmd.Args()[0])
+		fmt.Println("💡 使用できるサブコマンド: path, show, get, set, unset, list, doctor")
+		os.Exit(1)
+	}
+}
+
+// maskToken トークンを先頭4文字以外伏せ字にする
+func maskToken(token string) string {
+	if len(token) <= 4 {
+		return token
+	}
+	return token[:4] + "****"
+}
+
+func runDoctor(jsonOutput bool) {
+	cfg, err := config.Load()
+	workDir, _ := os.Getwd()
+
+	report := doctor.Run(context.Background(), doctor.Options{
+		ConfigPath: config.Path(),
+		Config:     cfg,
+		ConfigErr:  err,
+		NewClient: func(team, token string) doctor.Client {
+			return newAPIClient(team, token)
+		},
+		WorkDir: workDir,
+	})
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		icons := map[doctor.Status]string{
+			doctor.StatusOK:    "✅",
+			doctor.StatusWarn:  "⚠️ ",
+			doctor.StatusError: "❌",
+			doctor.StatusSkip:  "⏭️ ",
+		}
+		fmt.Println("🩺 esa-cli 診断")
+		for _, c := range report.Checks {
+			fmt.Printf("%s %-12s %s\n", icons[c.Status], c.Name, c.Message)
+			if c.Fix != "" {
+				fmt.Printf("   💡 %s\n", c.Fix)
+			}
+		}
+	}
+
+	if !report.OK {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/mac"
+	"github.com/shellme/esa-cli/internal/workspace"
+)
+
+// printWithPager ページャーが設定されていればページャー経由で出力する
+func printWithPager(pager, text string) {
+	if pager == "" {
+		fmt.Print(text)
+		return
+	}
+
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		// ページャーが使えない場合はそのまま出力
+		fmt.Print(text)
+		return
+	}
+	// ページャーが表示した後の終了コード（q で途中で閉じた場合など）は無視する
+	// sh がコマンドを見つけられなかった場合（127）は何も表示されていないのでそのまま出力する
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); errors.As(err, &exitErr) && exitErr.ExitCode() == 127 {
+		fmt.Print(text)
+	}
+}
+
+// openInEditor 設定されたエディタ（無ければ $EDITOR）でファイルを開く
+func openInEditor(editor, fileName string) error {
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return mac.OpenInEditor(fileName)
+	}
+
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", fileName)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// postFilePath 記事を保存するパスを設定（出力先・ファイル名テンプレート）に従って生成する
+// 別の記事のファイルがあれば上書きしないよう番号を付ける
+// 必要なディレクトリは作成する
+func postFilePath(defaults config.Defaults, number int, name, category string) (string, error) {
+	fileName := defaults.FileName(number, name, category)
+	path := workspace.PostPath(filepath.Join(defaults.OutputDir, fileName), number)
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestPrintWithPager(t *testing.T) {
+	tests := []struct {
+		name  string
+		pager string
+		want  string
+	}{
+		{
+			name:  "正常系：ページャーが無ければそのまま出力",
+			pager: "",
+			want:  "本文\n",
+		},
+		{
+			name:  "正常系：ページャー経由で出力",
+			pager: "cat",
+			want:  "本文\n",
+		},
+		{
+			name:  "正常系：表示した後にページャーが失敗しても二重に出力しない",
+			pager: "cat; exit 1",
+			want:  "本文\n",
+		},
+		{
+			name:  "異常系：ページャーのコマンドが見つからなければそのまま出力",
+			pager: "esa-cli-no-such-pager 2>/dev/null",
+			want:  "本文\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			stdout := os.Stdout
+			os.Stdout = w
+			printWithPager(tt.pager, "本文\n")
+			os.Stdout = stdout
+			w.Close()
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("出力 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// ArchiveCategory on_local_delete=archive で記事を移動するカテゴリ（元のカテゴリの前に付ける）
+const ArchiveCategory = "Archived"
+
+// remoteDeleted 同期するカテゴリに見つからなくなった記事を扱う
+// 記事が削除されていれば on_remote_delete に従ってローカルファイルを .trash に移動し、
+// カテゴリ外に移動されただけならファイルはそのまま残す
+func (s *syncer) remoteDeleted(item workspace.Item) {
+	rel := s.state.Rel(item.Path)
+	post, err := s.client.FetchPost(context.Background(), item.Number)
+	if err == nil {
+		fmt.Printf("⚠️  カテゴリ外に移動されました: %s（→ %s）\n", rel, post.FullName)
+		return
+	}
+	if !errors.Is(err, api.ErrNotFound) {
+		fmt.Printf("❌ %s: 記事の取得に失敗しました: %v\n", rel, err)
+		s.failed++
+		return
+	}
+
+	if s.defaults.RemoteDeletePolicy() == config.RemoteDeleteKeep {
+		fmt.Printf("⚠️  リモートで削除されました: %s（on_remote_delete=keep のためファイルを残します）\n", rel)
+		return
+	}
+	if s.dryRun {
+		fmt.Printf("🗑️  リモートで削除: %s → %s/\n", rel, workspace.TrashDir)
+		s.trashed++
+		return
+	}
+	to, err := s.state.Trash(item.Number, item.Path)
+	if err != nil {
+		fmt.Printf("❌ %s: .trash への移動に失敗しました: %v\n", rel, err)
+		s.failed++
+		return
+	}
+	fmt.Printf("🗑️  リモートで削除: %s → %s\n", rel, s.state.Rel(to))
+	s.trashed++
+}
+
+// localDeleted 同期済みのファイルが削除された記事を on_local_delete に従って扱う
+// ignore に一致するファイルや隠しディレクトリ内のファイルも見つからない扱いになるため、
+// ファイルが本当に無いことを確かめてから記事をアーカイブ・削除する
+func (s *syncer) localDeleted(item workspace.Item) {
+	if _, err := os.Stat(s.state.Abs(item.Entry)); !os.IsNotExist(err) {
+		fmt.Printf("⏭️  対象外: [%d] %s（ファイルは残っています）\n", item.Number, item.Entry.Path)
+		return
+	}
+
+	policy := s.defaults.LocalDeletePolicy()
+	if policy == config.LocalDeletePrompt {
+		policy = s.ask(item)
+	}
+
+	switch policy {
+	case config.LocalDeleteArchive:
+		fmt.Printf("📦 アーカイブ: [%d] %s → %s/\n", item.Number, item.Entry.Path, ArchiveCategory)
+		s.do(s.archive(item), &s.archived)
+	case config.LocalDeleteDelete:
+		fmt.Printf("🗑️  記事を削除: [%d] %s\n", item.Number, item.Entry.Path)
+		s.do(s.remove(item), &s.deleted)
+	default:
+		fmt.Printf("⚠️  ローカルファイルがありません: [%d] %s\n", item.Number, item.Entry.Path)
+	}
+}
+
+// ask ローカルで削除された記事をどうするか確認する（on_local_delete=prompt）
+func (s *syncer) ask(item workspace.Item) string {
+	fmt.Printf("❓ ローカルファイルが削除されています: [%d] %s\n", item.Number, item.Entry.Path)
+	fmt.Print("   リモートの記事をどうしますか？ [a]rchive / [d]elete / [N]othing: ")
+
+	var answer string
+	fmt.Scanln(&answer)
+	switch strings.ToLower(answer) {
+	case "a", "archive":
+		return config.LocalDeleteArchive
+	case "d", "delete":
+		return config.LocalDeleteDelete
+	}
+	return config.LocalDeleteIgnore
+}
+
+// archive 記事を Archived/ カテゴリに移動して同期状態から外す
+func (s *syncer) archive(item workspace.Item) error {
+	if s.dryRun {
+		return nil
+	}
+	post := item.Post
+	if post == nil {
+		var err error
+		post, err = s.client.FetchPost(context.Background(), item.Number)
+		if errors.Is(err, api.ErrNotFound) {
+			// リモートでも削除されていれば記録を消すだけでよい
+			s.state.Untrack(item.Number)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := s.client.UpdatePost(context.Background(), item.Number, types.UpdatePostBody{
+		Category: archivedCategory(post.Category),
+		Wip:      post.Wip, // wip は省略できないため元の状態を送る
+		Message:  s.message,
+	})
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return err
+	}
+	s.state.Untrack(item.Number)
+	return nil
+}
+
+// remove 記事を削除して同期状態から外す
+func (s *syncer) remove(item workspace.Item) error {
+	if s.dryRun {
+		return nil
+	}
+	if err := s.client.DeletePost(context.Background(), item.Number); err != nil && !errors.Is(err, api.ErrNotFound) {
+		return err
+	}
+	s.state.Untrack(item.Number)
+	return nil
+}
+
+// archivedCategory アーカイブ先のカテゴリ（開発/メモ → Archived/開発/メモ）
+func archivedCategory(category string) string {
+	category = strings.Trim(category, "/")
+	if category == ArchiveCategory || strings.HasPrefix(category, ArchiveCategory+"/") {
+		return category
+	}
+	if category == "" {
+		return ArchiveCategory
+	}
+	return ArchiveCategory + "/" + category
+}
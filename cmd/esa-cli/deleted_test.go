@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestSyncer_RemoteDeleted(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		status    int
+		wantTrash bool
+		wantFail  bool
+	}{
+		{name: "正常系：削除された記事のファイルを .trash に移動する", status: http.StatusNotFound, wantTrash: true},
+		{name: "正常系：keep ならファイルを残す", policy: config.RemoteDeleteKeep, status: http.StatusNotFound},
+		{name: "正常系：カテゴリ外に移動された記事はファイルを残す", status: http.StatusOK},
+		{name: "異常系：記事を確認できなければ失敗にする", status: http.StatusInternalServerError, wantFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, "開発", "1-手順.md")
+			content := []byte("---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n本文\n")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state.Track(&types.Post{Number: 1, RevisionNumber: 1}, path, content)
+
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetResponse(testutil.CreateMockResponse(t, tt.status, `{"number": 1, "full_name": "設計/手順"}`), nil)
+			s := &syncer{
+				client:   api.NewClient("test-team", "token", mockClient),
+				state:    state,
+				defaults: config.Defaults{OnRemoteDelete: tt.policy},
+				category: "開発",
+			}
+			local, err := workspace.ScanFiles(root, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range state.Compare(local, nil) {
+				s.apply(item)
+			}
+
+			trashed := filepath.Join(root, workspace.TrashDir, "開発", "1-手順.md")
+			if _, err := os.Stat(trashed); (err == nil) != tt.wantTrash {
+				t.Errorf(".trash のファイル: %v, want %v", err, tt.wantTrash)
+			}
+			if _, err := os.Stat(path); (err == nil) == tt.wantTrash {
+				t.Errorf("元のファイル: %v, want 残す %v", err, !tt.wantTrash)
+			}
+			if _, tracked := state.Entry(1); tracked == tt.wantTrash {
+				t.Errorf("同期状態の記録 = %v, want %v", tracked, !tt.wantTrash)
+			}
+			if (s.failed > 0) != tt.wantFail {
+				t.Errorf("failed = %d, wantFail %v", s.failed, tt.wantFail)
+			}
+		})
+	}
+}
+
+func TestSyncer_LocalDeleted(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		status       int
+		wantRequest  string
+		wantCategory string
+		wantTracked  bool
+	}{
+		{name: "正常系：ignore なら何もしない", wantTracked: true},
+		{
+			name:         "正常系：archive なら Archived/ カテゴリに移動する",
+			policy:       config.LocalDeleteArchive,
+			status:       http.StatusOK,
+			wantRequest:  "PATCH /v1/teams/test-team/posts/1",
+			wantCategory: "Archived/開発/メモ",
+		},
+		{
+			name:        "正常系：delete なら記事を削除する",
+			policy:      config.LocalDeleteDelete,
+			status:      http.StatusNoContent,
+			wantRequest: "DELETE /v1/teams/test-team/posts/1",
+		},
+		{
+			name:        "エッジケース：リモートでも削除されていれば記録を消す",
+			policy:      config.LocalDeleteDelete,
+			status:      http.StatusNotFound,
+			wantRequest: "DELETE /v1/teams/test-team/posts/1",
+		},
+		{
+			name:        "異常系：削除に失敗したら記録を残す",
+			policy:      config.LocalDeleteDelete,
+			status:      http.StatusForbidden,
+			wantRequest: "DELETE /v1/teams/test-team/posts/1",
+			wantTracked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			post := &types.Post{Number: 1, Name: "手順", Category: "開発/メモ", Wip: true, RevisionNumber: 1}
+			state.Track(post, filepath.Join(root, "1-手順.md"), []byte("削除したファイル"))
+
+			var requests []string
+			var category string
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				if req.Body != nil {
+					var body struct {
+						Post types.UpdatePostBody `json:"post"`
+					}
+					data, _ := io.ReadAll(req.Body)
+					_ = json.Unmarshal(data, &body)
+					category = body.Post.Category
+				}
+				return testutil.CreateMockResponse(t, tt.status, `{"number": 1}`), nil
+			})
+			s := &syncer{
+				client:   api.NewClient("test-team", "token", mockClient),
+				state:    state,
+				defaults: config.Defaults{OnLocalDelete: tt.policy},
+				category: "開発",
+			}
+			for _, item := range state.Compare(nil, []*types.Post{post}) {
+				s.apply(item)
+			}
+
+			if tt.wantRequest == "" && len(requests) > 0 || tt.wantRequest != "" && (len(requests) != 1 || requests[0] != tt.wantRequest) {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequest)
+			}
+			if category != tt.wantCategory {
+				t.Errorf("category = %v, want %v", category, tt.wantCategory)
+			}
+			if _, tracked := state.Entry(1); tracked != tt.wantTracked {
+				t.Errorf("同期状態の記録 = %v, want %v", tracked, tt.wantTracked)
+			}
+		})
+	}
+}
+
+func TestSyncer_LocalDeletedIgnoredFile(t *testing.T) {
+	for _, policy := range []string{config.LocalDeleteArchive, config.LocalDeleteDelete} {
+		t.Run("異常系：ignore に一致する同期済みのファイルは "+policy+" しない", func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, "drafts", "1-手順.md")
+			content := []byte("---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n本文\n")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			post := &types.Post{Number: 1, Name: "手順", Category: "開発", RevisionNumber: 1}
+			state.Track(post, path, content)
+
+			var requests []string
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req.Method+" "+req.URL.Path)
+				return testutil.CreateMockResponse(t, http.StatusNoContent, ""), nil
+			})
+			s := &syncer{
+				client:   api.NewClient("test-team", "token", mockClient),
+				state:    state,
+				defaults: config.Defaults{OnLocalDelete: policy},
+				category: "開発",
+			}
+			// drafts/ を ignore にしたワークスペース
+			local, err := workspace.ScanFiles(root, func(p string) bool {
+				return filepath.Base(filepath.Dir(p)) == "drafts"
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range state.Compare(local, []*types.Post{post}) {
+				s.apply(item)
+			}
+
+			if len(requests) > 0 {
+				t.Errorf("requests = %v, want none", requests)
+			}
+			if _, tracked := state.Entry(1); !tracked {
+				t.Error("同期状態の記録が消えました")
+			}
+		})
+	}
+}
+
+func TestArchivedCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		want     string
+	}{
+		{name: "正常系：カテゴリの前に Archived を付ける", category: "開発/メモ", want: "Archived/開発/メモ"},
+		{name: "正常系：カテゴリが無ければ Archived", category: "", want: "Archived"},
+		{name: "エッジケース：アーカイブ済みならそのまま", category: "Archived/開発", want: "Archived/開発"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := archivedCategory(tt.category); got != tt.want {
+				t.Errorf("archivedCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
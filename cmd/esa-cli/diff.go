@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/naming"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/spf13/pflag"
+)
+
+// runDiff ローカルのファイルとリモートの記事の差分を表示する
+// diff(1) と同じく、差分が無ければ終了コード0、あれば1、エラーがあれば2で終了する
+func runDiff(cmd *pflag.FlagSet, all, wordDiff bool, context int, color string) {
+	if !all && len(cmd.Args()) < 1 {
+		fmt.Fprintln(os.Stderr, "❌ ファイル名を指定してください")
+		fmt.Fprintln(os.Stderr, "💡 使用例: esa-cli diff 123-title.md （ワークスペース全体は esa-cli diff --all）")
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 設定の読み込みに失敗しました: %v\n", err)
+		os.Exit(2)
+	}
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Fprintln(os.Stderr, "❌ 設定が完了していません")
+		fmt.Fprintln(os.Stderr, "💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(2)
+	}
+	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+
+	opts := diff.Options{Context: context, Words: wordDiff}
+	switch color {
+	case "always":
+		opts.Color = true
+	case "never":
+	case "auto":
+		opts.Color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		fmt.Fprintf(os.Stderr, "❌ --color には auto / always / never のいずれかを指定してください: %s\n", color)
+		os.Exit(2)
+	}
+
+	files := cmd.Args()
+	if all {
+		if files, err = workspacePostFiles(cfg.Project()); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ ファイルの読み込みに失敗しました: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	changed, failed := 0, 0
+	links := workspace.NewLinks(client.TeamName())
+	for _, file := range files {
+		differs, err := diffFile(os.Stdout, client, links, file, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			failed++
+			continue
+		}
+		if differs {
+			changed++
+		}
+	}
+
+	switch {
+	case failed > 0:
+		os.Exit(2)
+	case changed > 0:
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "✅ 差分はありません")
+}
+
+// workspacePostFiles ワークスペース内の記事番号のあるファイル（カレントディレクトリからの相対パス）
+func workspacePostFiles(project *config.ProjectConfig) ([]string, error) {
+	root, err := workspace.Root()
+	if err != nil {
+		return nil, err
+	}
+	local, err := workspace.ScanFiles(root, project.Ignored)
+	if err != nil {
+		return nil, err
+	}
+	wd, _ := os.Getwd()
+	var files []string
+	for _, f := range local {
+		if f.Number == 0 {
+			continue
+		}
+		path := f.Path
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// diffFile ファイルと対応するリモートの記事を比較し、差分があれば書き出す
+// 本文は update でアップロードする形（画像・リンクを元に戻した状態）で比較する
+func diffFile(w io.Writer, client *api.Client, links *workspace.Links, path string, opts diff.Options) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("ファイルの読み込みに失敗: %v", err)
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		return false, fmt.Errorf("ファイルの解析に失敗: %v", err)
+	}
+	number := fm.Number
+	if number == 0 {
+		n, ok := naming.NumberFromFileName(path)
+		if !ok {
+			return false, fmt.Errorf("記事番号が分かりません")
+		}
+		number = n
+	}
+	if fm.Team != "" && fm.Team != client.TeamName() {
+		return false, fmt.Errorf("チーム %s の記事です（現在のチーム: %s）", fm.Team, client.TeamName())
+	}
+
+	post, err := client.FetchPost(context.Background(), number)
+	if errors.Is(err, api.ErrNotFound) {
+		return false, fmt.Errorf("記事 #%d がリモートにありません（削除された可能性があります）", number)
+	}
+	if err != nil {
+		return false, fmt.Errorf("記事の取得に失敗: %v", err)
+	}
+
+	upload := convert.ToUpload(links, number, body, filepath.Dir(path)).Text
+	fields := []diff.Field{
+		{Name: "title", Old: post.Name, New: fm.Title},
+		{Name: "category", Old: post.Category, New: fm.Category},
+		{Name: "tags", Old: strings.Join(post.Tags, ", "), New: strings.Join(fm.Tags, ", ")},
+		{Name: "wip", Old: strconv.FormatBool(post.Wip), New: strconv.FormatBool(fm.Wip)},
+	}
+	edits := diff.Lines(post.BodyMd, upload)
+
+	fieldsChanged := false
+	for _, f := range fields {
+		if f.Old != f.New {
+			fieldsChanged = true
+		}
+	}
+	if !fieldsChanged && !diff.Changed(edits) {
+		return false, nil
+	}
+
+	diff.WriteHeader(w, fmt.Sprintf("#%d %s (esa)", number, post.FullName), path+" (ローカル)", opts)
+	diff.WriteFields(w, fields, opts)
+	diff.WriteUnified(w, edits, opts)
+	return true, nil
+}
+
+// isTerminal 出力先が端末か
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestDiffFile(t *testing.T) {
+	root := testutil.CreateTempDir(t)
+	chdir(t, root)
+
+	remote := types.Post{
+		Number:   1,
+		Name:     "設計メモ",
+		FullName: "開発/設計メモ",
+		Category: "開発",
+		Tags:     []string{"go"},
+		Wip:      true,
+		BodyMd:   "# 概要\r\n今日は晴れです。\r\n",
+	}
+	mockClient := mock.NewMockHTTPClient()
+	mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "/posts/1") {
+			return testutil.CreateMockResponse(t, http.StatusNotFound, `{"error":"not_found"}`), nil
+		}
+		data, _ := json.Marshal(remote)
+		return testutil.CreateMockResponse(t, http.StatusOK, string(data)), nil
+	})
+	client := api.NewClient("test-team", "token", mockClient)
+
+	tests := []struct {
+		name    string
+		content string
+		opts    diff.Options
+		want    string
+		differs bool
+		wantErr bool
+	}{
+		{
+			name:    "正常系：メタデータと本文の差分",
+			content: "---\ntitle: 設計メモ\ncategory: 開発\ntags: [go]\nwip: false\nnumber: 1\n---\n# 概要\n今日は雨です。\n",
+			opts:    diff.Options{Context: 3},
+			want: "--- #1 開発/設計メモ (esa)\n+++ 1-設計メモ.md (ローカル)\n" +
+				"-wip: true\n+wip: false\n" +
+				"@@ -1,2 +1,2 @@\n # 概要\n-今日は晴れです。\n+今日は雨です。\n",
+			differs: true,
+		},
+		{
+			name:    "正常系：単語単位の差分",
+			content: "---\ntitle: 設計メモ\ncategory: 開発\ntags: [go]\nwip: true\nnumber: 1\n---\n# 概要\n今日は雨です。\n",
+			opts:    diff.Options{Context: 0, Words: true},
+			want:    "--- #1 開発/設計メモ (esa)\n+++ 1-設計メモ.md (ローカル)\n@@ -2,1 +2,1 @@\n今日は[-晴れ-]{+雨+}です。\n",
+			differs: true,
+		},
+		{
+			name:    "正常系：差分が無ければ何も表示しない",
+			content: "---\ntitle: 設計メモ\ncategory: 開発\ntags: [go]\nwip: true\nnumber: 1\n---\n# 概要\n今日は晴れです。\n",
+			opts:    diff.Options{Context: 3},
+		},
+		{
+			name:    "異常系：リモートに記事が無い",
+			content: "---\ntitle: 削除済み\nnumber: 2\n---\n本文\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "1-設計メモ.md"
+			if err := os.WriteFile(filepath.Join(root, path), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var buf strings.Builder
+			differs, err := diffFile(&buf, client, workspace.NewLinks("test-team"), path, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("diffFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if differs != tt.differs {
+				t.Errorf("diffFile() = %v, want %v", differs, tt.differs)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("diffFile() の出力 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shellme/esa-cli/internal/api"
 	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
 	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/naming"
+	"github.com/shellme/esa-cli/internal/templates"
+	"github.com/shellme/esa-cli/internal/workspace"
 	"github.com/shellme/esa-cli/pkg/types"
 	"github.com/spf13/pflag"
 )
@@ -34,6 +40,13 @@ func main() {
 
 	// コマンドライン引数の解析
 	setupCmd := pflag.NewFlagSet("setup", pflag.ExitOnError)
+	var setupTeam string
+	var setupTokenStdin bool
+	var setupNoVerify bool
+	setupCmd.StringVar(&setupTeam, "team", "", "チーム名（サブドメイン）")
+	setupCmd.BoolVar(&setupTokenStdin, "token-stdin", false, "アクセストークンを標準入力から読み込む（--team が必要）")
+	setupCmd.BoolVar(&setupNoVerify, "no-verify", false, "接続テストを行わない")
+	setupCmd.StringVar(&config.SelectedProfile, "profile", "", "保存先のプロファイル名")
 	listCmd := pflag.NewFlagSet("list", pflag.ExitOnError)
 	fetchCmd := pflag.NewFlagSet("fetch", pflag.ExitOnError)
 	updateCmd := pflag.NewFlagSet("update", pflag.ExitOnError)
@@ -47,6 +60,8 @@ func main() {
 	listCmd.StringVarP(&tag, "tag", "t", "", "タグでフィルタリング")
 	listCmd.StringVarP(&query, "query", "q", "", "検索ワードでフィルタリング")
 	listCmd.StringVarP(&user, "user", "u", "", "作成者でフィルタリング")
+	var noPager bool
+	listCmd.BoolVar(&noPager, "no-pager", false, "ページャーを使わずに表示")
 
 	// fetchコマンドのオプション
 	var fetchCategory string
@@ -61,6 +76,12 @@ func main() {
 	fetchCmd.StringVarP(&fetchUser, "user", "u", "", "作成者でフィルタリング")
 	fetchCmd.BoolVarP(&fetchLatest, "latest", "l", false, "最新の記事をダウンロード")
 	fetchCmd.BoolVarP(&fetchPrint, "print", "p", false, "ファイルに保存せず標準出力に表示")
+	var fetchLocalLinks bool
+	fetchCmd.BoolVar(&fetchLocalLinks, "local-links", false, "ワークスペース内にある記事へのリンクをローカルファイルへのリンクに書き換える")
+	var fetchWithAssets bool
+	fetchCmd.BoolVar(&fetchWithAssets, "with-assets", false, "記事中の画像を assets/ にダウンロードしてローカルのパスに書き換える")
+	var fetchTree bool
+	fetchCmd.BoolVar(&fetchTree, "tree", false, "カテゴリをディレクトリにして保存する（開発/API/123-タイトル.md）")
 
 	// updateコマンドのオプション
 	var noWip bool
@@ -107,6 +128,104 @@ func main() {
 	createCmd.BoolVarP(&createWip, "wip", "w", false, "WIP状態で作成")
 	createCmd.StringVarP(&createFile, "file", "f", "", "既存のMarkdownファイルから作成")
 	createCmd.BoolVarP(&createTemplate, "template", "T", false, "esa.ioにアップロードせず、ローカルにテンプレートファイルのみ生成")
+	var createEdit bool
+	createCmd.BoolVarP(&createEdit, "edit", "e", false, "作成したローカルファイルをエディタで開く")
+	var createFromTemplate string
+	createCmd.StringVar(&createFromTemplate, "from-template", "", "esa.ioのテンプレート記事（記事番号またはタイトル）から作成")
+
+	// templatesコマンドのオプション
+	templatesCmd := pflag.NewFlagSet("templates", pflag.ExitOnError)
+
+	// profileコマンドのオプション
+	profileCmd := pflag.NewFlagSet("profile", pflag.ExitOnError)
+	var profileTeam string
+	var profileToken string
+	profileCmd.StringVar(&profileTeam, "team-name", "", "チーム名（profile add）")
+	profileCmd.StringVar(&profileToken, "token", "", "アクセストークン（profile add）")
+	var profileHelper string
+	profileCmd.StringVar(&profileHelper, "credential-helper", "", "トークンを取得するクレデンシャルヘルパー（profile add）")
+
+	// configコマンドのオプション
+	configCmd := pflag.NewFlagSet("config", pflag.ExitOnError)
+	var configOrigin bool
+	configCmd.BoolVar(&configOrigin, "origin", false, "各設定値の出どころを表示")
+	var configJSON bool
+	configCmd.BoolVar(&configJSON, "json", false, "診断結果をJSONで出力（config doctor）")
+
+	// migrateコマンドのオプション
+	migrateCmd := pflag.NewFlagSet("migrate", pflag.ExitOnError)
+	var migrateDryRun bool
+	migrateCmd.BoolVar(&migrateDryRun, "dry-run", false, "変更するファイルを表示するだけで書き込まない")
+
+	// syncコマンドのオプション
+	syncCmd := pflag.NewFlagSet("sync", pflag.ExitOnError)
+	var syncCategory string
+	var syncDryRun bool
+	var syncMessage string
+	syncCmd.StringVarP(&syncCategory, "category", "c", "", "同期するカテゴリ（省略時は .esa-cli.yml の category）")
+	syncCmd.BoolVar(&syncDryRun, "dry-run", false, "同期の内容を表示するだけで変更しない")
+	syncCmd.StringVarP(&syncMessage, "message", "m", "", "更新メッセージ")
+
+	// pullコマンドのオプション
+	pullCmd := pflag.NewFlagSet("pull", pflag.ExitOnError)
+	var pullCategory string
+	var pullFull bool
+	var pullNoMerge bool
+	var pullDryRun bool
+	pullCmd.StringVarP(&pullCategory, "category", "c", "", "取得するカテゴリ（省略時は .esa-cli.yml の category）")
+	pullCmd.BoolVar(&pullFull, "full", false, "前回の取得時刻を使わず、カテゴリの記事をすべて確認")
+	pullCmd.BoolVar(&pullNoMerge, "no-merge", false, "ローカルで変更したファイルはマージせずにスキップ")
+	pullCmd.BoolVar(&pullDryRun, "dry-run", false, "取り込む内容を表示するだけで変更しない")
+
+	// pushコマンドのオプション
+	pushCmd := pflag.NewFlagSet("push", pflag.ExitOnError)
+	var pushMessage string
+	var pushForce bool
+	var pushDryRun bool
+	pushCmd.StringVarP(&pushMessage, "message", "m", "", "更新メッセージ")
+	pushCmd.BoolVarP(&pushForce, "force", "f", false, "リモートが更新されていても上書き")
+	pushCmd.BoolVar(&pushDryRun, "dry-run", false, "反映する内容を表示するだけで変更しない")
+
+	// watchコマンドのオプション
+	watchCmd := pflag.NewFlagSet("watch", pflag.ExitOnError)
+	var watchInterval time.Duration
+	var watchDebounce time.Duration
+	var watchMessage string
+	watchCmd.DurationVar(&watchInterval, "interval", time.Second, "ファイルの変更を確認する間隔")
+	watchCmd.DurationVar(&watchDebounce, "debounce", 2*time.Second, "保存してから反映するまで待つ時間（この間に保存されたら待ち直す）")
+	watchCmd.StringVarP(&watchMessage, "message", "m", "", "更新メッセージ")
+
+	// statusコマンドのオプション
+	statusCmd := pflag.NewFlagSet("status", pflag.ExitOnError)
+	var statusCategory string
+	var statusLocal bool
+	var statusJSON bool
+	statusCmd.StringVarP(&statusCategory, "category", "c", "", "確認するカテゴリ（省略時は .esa-cli.yml の category）")
+	statusCmd.BoolVar(&statusLocal, "local", false, "リモートを確認せず、ローカルの変更だけを表示")
+	statusCmd.BoolVar(&statusJSON, "json", false, "JSONで出力")
+
+	// diffコマンドのオプション
+	diffCmd := pflag.NewFlagSet("diff", pflag.ExitOnError)
+	var diffAll bool
+	var diffWords bool
+	var diffContext int
+	var diffColor string
+	diffCmd.BoolVar(&diffAll, "all", false, "ワークスペース内の記事ファイルをすべて比較")
+	diffCmd.BoolVar(&diffWords, "word-diff", false, "変更された行を単語単位（日本語は1文字単位）で表示")
+	diffCmd.IntVarP(&diffContext, "unified", "U", 3, "変更の前後に表示する行数")
+	diffCmd.StringVar(&diffColor, "color", "auto", "色付けの有無（auto / always / never）")
+
+	// previewコマンドのオプション
+	previewCmd := pflag.NewFlagSet("preview", pflag.ExitOnError)
+	var previewPort int
+	var previewOpen bool
+	previewCmd.IntVar(&previewPort, "port", 0, "待ち受けるポート番号（0の場合は空いているポートを使う）")
+	previewCmd.BoolVarP(&previewOpen, "open", "o", false, "ブラウザで開く")
+
+	// 全コマンド共通のオプション
+	for _, fs := range []*pflag.FlagSet{listCmd, fetchCmd, updateCmd, moveCmd, createCmd, configCmd, migrateCmd, templatesCmd, syncCmd, pullCmd, pushCmd, watchCmd, statusCmd, diffCmd} {
+		addGlobalFlags(fs)
+	}
 
 	// 引数が指定されていない場合はヘルプを表示
 	if len(os.Args) < 2 {
@@ -118,13 +237,13 @@ func main() {
 	switch os.Args[1] {
 	case "setup":
 		setupCmd.Parse(os.Args[2:])
-		runSetup()
+		runSetup(setupTeam, setupTokenStdin, setupNoVerify)
 	case "list":
 		listCmd.Parse(os.Args[2:])
-		runList(listCmd, category, tag, query, user)
+		runList(listCmd, category, tag, query, user, noPager)
 	case "fetch":
 		fetchCmd.Parse(os.Args[2:])
-		runFetch(fetchCmd, fetchCategory, fetchTag, fetchQuery, fetchUser, fetchLatest, fetchPrint)
+		runFetch(fetchCmd, fetchCategory, fetchTag, fetchQuery, fetchUser, fetchLatest, fetchPrint, fetchLocalLinks, fetchWithAssets, fetchTree)
 	case "update":
 		updateCmd.Parse(os.Args[2:])
 		runUpdate(updateCmd, noWip, updateCategory, addTags, removeTags, message)
@@ -133,7 +252,40 @@ func main() {
 		runMove(moveCmd, moveCategory, moveUser, moveQuery, moveTag, moveToCategory, moveMessage, moveForce)
 	case "create":
 		createCmd.Parse(os.Args[2:])
-		runCreate(createCmd, createTitle, createCategory, createTags, createMessage, createWip, createFile, createTemplate)
+		runCreate(createCmd, createTitle, createCategory, createTags, createMessage, createWip, createFile, createTemplate, createEdit, createFromTemplate)
+	case "templates":
+		templatesCmd.Parse(os.Args[2:])
+		runTemplates(templatesCmd)
+	case "profile":
+		profileCmd.Parse(os.Args[2:])
+		runProfile(profileCmd, profileTeam, profileToken, profileHelper)
+	case "config":
+		configCmd.Parse(os.Args[2:])
+		runConfig(configCmd, configOrigin, configJSON)
+	case "migrate":
+		migrateCmd.Parse(os.Args[2:])
+		runMigrate(migrateCmd, migrateDryRun)
+	case "sync":
+		syncCmd.Parse(os.Args[2:])
+		runSync(syncCmd, syncCategory, syncDryRun, syncMessage)
+	case "pull":
+		pullCmd.Parse(os.Args[2:])
+		runPull(pullCmd, pullCategory, pullFull, pullNoMerge, pullDryRun)
+	case "push":
+		pushCmd.Parse(os.Args[2:])
+		runPush(pushCmd, pushMessage, pushForce, pushDryRun)
+	case "watch":
+		watchCmd.Parse(os.Args[2:])
+		runWatch(watchCmd, watchInterval, watchDebounce, watchMessage)
+	case "status":
+		statusCmd.Parse(os.Args[2:])
+		runStatus(statusCmd, statusCategory, statusLocal, statusJSON)
+	case "diff":
+		diffCmd.Parse(os.Args[2:])
+		runDiff(diffCmd, diffAll, diffWords, diffContext, diffColor)
+	case "preview":
+		previewCmd.Parse(os.Args[2:])
+		runPreview(previewCmd, previewPort, previewOpen)
 	case "help":
 		showHelp()
 	default:
@@ -147,6 +299,11 @@ func showHelp() {
 	fmt.Printf("esa-cli %s - esaの記事をローカルで編集するCLIツール\n\n", version)
 	fmt.Println("使用方法:")
 	fmt.Println("  esa-cli setup                 初期設定")
+	fmt.Println("    オプション:")
+	fmt.Println("      --team <チーム名>          チーム名（サブドメイン）")
+	fmt.Println("      --token-stdin             アクセストークンを標準入力から読み込む")
+	fmt.Println("      --no-verify               接続テストを行わない")
+	fmt.Println("      --profile <名前>          保存先のプロファイル名")
 	fmt.Println("  esa-cli list [件数]            記事一覧を表示（デフォルト10件）")
 	fmt.Println("    例: esa-cli list 20          # 最新20件を表示")
 	fmt.Println("    オプション:")
@@ -154,6 +311,7 @@ func showHelp() {
 	fmt.Println("      -t, --tag <タグ>          タグでフィルタリング")
 	fmt.Println("      -q, --query <検索ワード>   検索ワードでフィルタリング")
 	fmt.Println("      -u, --user <作成者>       作成者でフィルタリング")
+	fmt.Println("      --no-pager                ページャーを使わずに表示")
 	fmt.Println("  esa-cli fetch <記事番号>       記事をダウンロード")
 	fmt.Println("    オプション:")
 	fmt.Println("      -c, --category <カテゴリ>  カテゴリでフィルタリング")
@@ -162,6 +320,9 @@ func showHelp() {
 	fmt.Println("      -u, --user <作成者>       作成者でフィルタリング")
 	fmt.Println("      -l, --latest              最新の記事をダウンロード")
 	fmt.Println("      -p, --print               ファイルに保存せず標準出力に表示")
+	fmt.Println("      --local-links             ワークスペース内の記事へのリンクをローカルファイルへのリンクに書き換え")
+	fmt.Println("      --with-assets             記事中の画像を assets/ にダウンロード")
+	fmt.Println("      --tree                    カテゴリをディレクトリにして保存（開発/API/123-タイトル.md）")
 	fmt.Println("  esa-cli update <ファイル名>    記事を更新")
 	fmt.Println("    オプション:")
 	fmt.Println("      -n, --no-wip              WIP状態を解除")
@@ -175,10 +336,10 @@ func showHelp() {
 	fmt.Println("      -u, --user <作成者>       作成者でフィルタリング")
 	fmt.Println("      -q, --query <検索ワード>   検索ワードでフィルタリング")
 	fmt.Println("      -t, --tag <タグ>          タグでフィルタリング")
-	fmt.Println("      -o, --to <移動先カテゴリ>  移動先のカテゴリ（必須）")
+	fmt.Println("      -o, --to <移動先カテゴリ>  移動先のカテゴリ（必須、%{Year} などを展開）")
 	fmt.Println("      -m, --message <メッセージ> 移動メッセージ")
 	fmt.Println("      -f, --force               確認なしで実行")
-	fmt.Println("  esa-cli create                 新しい記事を作成")
+	fmt.Println("  esa-cli create                 新しい記事を作成（タイトル・カテゴリの %{Year} などを展開）")
 	fmt.Println("    オプション:")
 	fmt.Println("      -t, --title <記事のタイトル>  記事のタイトル")
 	fmt.Println("      -c, --category <カテゴリ>  カテゴリ")
@@ -187,11 +348,88 @@ func showHelp() {
 	fmt.Println("      -w, --wip                 WIP状態で作成")
 	fmt.Println("      -f, --file <既存のMarkdownファイル> 既存のMarkdownファイルから作成")
 	fmt.Println("      -T, --template            ローカルにテンプレートファイルのみ生成（esa.ioにアップロードしない）")
+	fmt.Println("      -e, --edit                作成したローカルファイルをエディタで開く")
+	fmt.Println("      --from-template <番号|タイトル> esa.ioのテンプレート記事から作成（-T と組み合わせるとローカルの下書きに展開）")
+	fmt.Println("  esa-cli templates              esa.ioのテンプレート記事の一覧を表示")
+	fmt.Println("  esa-cli profile list           プロファイル一覧を表示")
+	fmt.Println("  esa-cli profile use <名前>     デフォルトのプロファイルを切り替え")
+	fmt.Println("  esa-cli profile add <名前>     プロファイルを追加")
+	fmt.Println("    オプション:")
+	fmt.Println("      --team-name <チーム名>      チーム名（サブドメイン）")
+	fmt.Println("      --token <トークン>          アクセストークン")
+	fmt.Println("      --credential-helper <コマンド> トークンを保存・取得する外部コマンド")
+	fmt.Println("  esa-cli profile remove <名前>  プロファイルを削除")
+	fmt.Println("  esa-cli config path            使用中の設定ファイルのパスを表示")
+	fmt.Println("  esa-cli config show            有効な設定を表示")
+	fmt.Println("    オプション:")
+	fmt.Println("      --origin                  各設定値の出どころを表示")
+	fmt.Println("  esa-cli config list            コマンドのデフォルト値を一覧表示")
+	fmt.Println("  esa-cli config get <キー>      デフォルト値を表示")
+	fmt.Println("  esa-cli config set <キー> <値> デフォルト値を設定")
+	fmt.Println("  esa-cli config unset <キー>    デフォルト値を削除")
+	fmt.Println("    キー: category, tags, message_template, output_dir, filename_template,")
+	fmt.Println("          wip, list_limit, editor, pager, merge_tool, front_matter_format, tree,")
+	fmt.Println("          on_remote_delete, on_local_delete")
+	fmt.Println("  esa-cli config doctor          設定・トークン・接続・ワークスペースを診断")
+	fmt.Println("    オプション:")
+	fmt.Println("      --json                    診断結果をJSONで出力")
+	fmt.Println("  esa-cli migrate [ディレクトリ]  Front Matterに記事番号・チーム名を書き込む")
+	fmt.Println("    オプション:")
+	fmt.Println("      --dry-run                 変更するファイルを表示するだけで書き込まない")
+	fmt.Println("  esa-cli sync                   ワークスペースとカテゴリの記事を双方向に同期")
+	fmt.Println("    オプション:")
+	fmt.Println("      -c, --category <カテゴリ>  同期するカテゴリ（省略時は .esa-cli.yml の category）")
+	fmt.Println("      --dry-run                 同期の内容を表示するだけで変更しない")
+	fmt.Println("      -m, --message <メッセージ> 更新メッセージ")
+	fmt.Println("    削除の扱い:")
+	fmt.Println("      リモートで削除された記事    on_remote_delete: trash（.trash に移動、デフォルト）, keep")
+	fmt.Println("      ローカルで削除したファイル  on_local_delete: ignore（デフォルト）, prompt, archive（Archived/ に移動）, delete")
+	fmt.Println("  esa-cli pull                   前回の取得以降にリモートで変更された記事だけを取り込む")
+	fmt.Println("    オプション:")
+	fmt.Println("      -c, --category <カテゴリ>  取得するカテゴリ（省略時は .esa-cli.yml の category）")
+	fmt.Println("      --full                    前回の取得時刻を使わず、カテゴリの記事をすべて確認")
+	fmt.Println("      --no-merge                ローカルで変更したファイルはマージせずにスキップ")
+	fmt.Println("      --dry-run                 取り込む内容を表示するだけで変更しない")
+	fmt.Println("  esa-cli push [ファイル名...]   最後に同期してから変更したファイルだけを反映")
+	fmt.Println("    オプション:")
+	fmt.Println("      -m, --message <メッセージ> 更新メッセージ")
+	fmt.Println("      -f, --force               リモートが更新されていても上書き")
+	fmt.Println("      --dry-run                 反映する内容を表示するだけで変更しない")
+	fmt.Println("  esa-cli watch [ディレクトリ]   ファイルを監視し、保存したら自動で反映（wip の指定が無ければWIPのまま）")
+	fmt.Println("    オプション:")
+	fmt.Println("      --interval <間隔>         ファイルの変更を確認する間隔（デフォルト: 1s）")
+	fmt.Println("      --debounce <時間>         保存してから反映するまで待つ時間（デフォルト: 2s）")
+	fmt.Println("      -m, --message <メッセージ> 更新メッセージ")
+	fmt.Println("  esa-cli status                 ローカルとリモートの変更を表示")
+	fmt.Println("    オプション:")
+	fmt.Println("      -c, --category <カテゴリ>  確認するカテゴリ（省略時は .esa-cli.yml の category）")
+	fmt.Println("      --local                   リモートを確認せず、ローカルの変更だけを表示")
+	fmt.Println("      --json                    JSONで出力")
+	fmt.Println("  esa-cli diff <ファイル名>      ローカルのファイルとリモートの記事の差分を表示（差分があれば終了コード1）")
+	fmt.Println("    オプション:")
+	fmt.Println("      --all                     ワークスペース内の記事ファイルをすべて比較")
+	fmt.Println("      --word-diff               変更された行を単語単位（日本語は1文字単位）で表示")
+	fmt.Println("      -U, --unified <行数>      変更の前後に表示する行数（デフォルト: 3）")
+	fmt.Println("      --color <auto|always|never> 色付けの有無（デフォルト: auto）")
+	fmt.Println("  esa-cli preview <ファイル名>   ローカルのサーバーで記事をプレビュー（保存すると自動で再読み込み）")
+	fmt.Println("    オプション:")
+	fmt.Println("      --port <ポート番号>        待ち受けるポート番号（デフォルトは空いているポート）")
+	fmt.Println("      -o, --open                ブラウザで開く")
 	fmt.Println("  esa-cli version                バージョン表示")
 	fmt.Println("  esa-cli help                   このヘルプを表示")
 	fmt.Println("")
+	fmt.Println("共通オプション:")
+	fmt.Println("  --profile, --team <名前>       使用するプロファイル（環境変数 ESA_PROFILE でも指定可）")
+	fmt.Println("")
+	fmt.Println("環境変数:")
+	fmt.Println("  ESA_PROFILE                    使用するプロファイル")
+	fmt.Println("  ESA_TEAM                       チーム名（プロファイル名またはチーム名）")
+	fmt.Println("  ESA_ACCESS_TOKEN               アクセストークン")
+	fmt.Println("  設定の優先順位: フラグ > 環境変数 > プロジェクト設定(.esa-cli.yml) > ユーザー設定")
+	fmt.Println("")
 	fmt.Println("例:")
 	fmt.Println("  esa-cli setup                  # 初回設定")
+	fmt.Println("  echo $TOKEN | esa-cli setup --team my-team --token-stdin  # 非対話で設定")
 	fmt.Println("  esa-cli list                   # 最新10件の記事一覧")
 	fmt.Println("  esa-cli list -c 開発            # 開発カテゴリの記事一覧")
 	fmt.Println("  esa-cli list -t API             # APIタグの記事一覧")
@@ -215,20 +453,31 @@ func showHelp() {
 	fmt.Println("  esa-cli create -f draft.md -c 開発/ドキュメント  # 既存ファイルから記事を作成")
 	fmt.Println("  esa-cli create \"下書き記事\" -T  # ローカルにテンプレートファイルのみ生成")
 	fmt.Println("  esa-cli create \"技術記事\" -c 技術/Go -g Go,技術記事 -T  # カテゴリ・タグ付きテンプレートを生成")
+	fmt.Println("  esa-cli profile add work --team-name my-company --token xxxx  # プロファイルを追加")
+	fmt.Println("  esa-cli list --profile work    # workプロファイルのチームで記事一覧")
+	fmt.Println("  esa-cli config set category 開発/メモ  # createのデフォルトカテゴリを設定")
 	fmt.Println("")
 	fmt.Println("💡 初回利用時は 'esa-cli setup' で設定を行ってください")
 }
 
-func runSetup() {
+func runSetup(team string, tokenStdin, noVerify bool) {
+	opts := config.SetupOptions{
+		TeamName: team,
+		NoVerify: noVerify,
+	}
+	if tokenStdin {
+		opts.TokenReader = os.Stdin
+	}
+
 	// 一時的なクライアントを作成
 	client := api.NewClient("", "", http.DefaultClient)
-	if err := config.Setup(client); err != nil {
+	if err := config.SetupWithOptions(client, opts); err != nil {
 		fmt.Printf("❌ エラー: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func runList(cmd *pflag.FlagSet, category, tag, query, user string) {
+func runList(cmd *pflag.FlagSet, category, tag, query, user string, noPager bool) {
 	options := &api.ListPostsOptions{
 		Category: "", // カテゴリはAPIパラメータとして使わず、クライアント側でフィルタリング
 		Tag:      tag,
@@ -257,6 +506,15 @@ func runList(cmd *pflag.FlagSet, category, tag, query, user string) {
 
 	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
 
+	// 件数の指定が無ければ設定のデフォルトを使う
+	defaults := cfg.GetDefaults()
+	if len(cmd.Args()) == 0 && defaults.ListLimit > 0 {
+		options.Limit = defaults.ListLimit
+	}
+	if noPager {
+		defaults.Pager = ""
+	}
+
 	// 検索条件の表示
 	fmt.Println("🔍 記事を検索中...")
 	if category != "" {
@@ -332,13 +590,15 @@ func runList(cmd *pflag.FlagSet, category, tag, query, user string) {
 		return
 	}
 
-	fmt.Printf("📋 記事一覧 (%d件):\n", len(posts))
+	var out strings.Builder
+	fmt.Fprintf(&out, "📋 記事一覧 (%d件):\n", len(posts))
 	for _, post := range posts {
-		fmt.Printf("  [%d] %s\n", post.Number, post.FullName)
+		fmt.Fprintf(&out, "  [%d] %s\n", post.Number, post.FullName)
 	}
+	printWithPager(defaults.Pager, out.String())
 }
 
-func runFetch(cmd *pflag.FlagSet, category, tag, query, user string, latest bool, printToStdout bool) {
+func runFetch(cmd *pflag.FlagSet, category, tag, query, user string, latest bool, printToStdout bool, localLinks bool, withAssets bool, tree bool) {
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
@@ -353,8 +613,15 @@ func runFetch(cmd *pflag.FlagSet, category, tag, query, user string, latest bool
 	}
 
 	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+	defaults := cfg.GetDefaults()
+	if cmd.Changed("tree") {
+		defaults.Tree = &tree
+	}
 
 	if latest {
+		// プロジェクト設定のルートカテゴリ配下から探す
+		category = cfg.Project().CategoryFor(category)
+
 		// 最新の記事を取得
 		options := &api.ListPostsOptions{
 			Category: category,
@@ -408,7 +675,7 @@ func runFetch(cmd *pflag.FlagSet, category, tag, query, user string, latest bool
 			fmt.Printf("📥 最新記事をダウンロード中: [%d] %s\n", post.Number, post.FullName)
 		}
 		// 最新記事の番号で後続の処理を行う
-		fetchArticle(client, post.Number, printToStdout)
+		fetchArticle(client, defaults, post.Number, printToStdout, localLinks, withAssets)
 		return
 	}
 
@@ -425,11 +692,11 @@ func runFetch(cmd *pflag.FlagSet, category, tag, query, user string, latest bool
 		os.Exit(1)
 	}
 
-	fetchArticle(client, postNumber, printToStdout)
+	fetchArticle(client, defaults, postNumber, printToStdout, localLinks, withAssets)
 }
 
 // 記事を取得してファイルに書き込む共通関数
-func fetchArticle(client *api.Client, postNumber int, printToStdout bool) {
+func fetchArticle(client *api.Client, defaults config.Defaults, postNumber int, printToStdout bool, localLinks bool, withAssets bool) {
 	// 記事を取得
 	post, err := client.FetchPost(context.Background(), postNumber)
 	if err != nil {
@@ -437,15 +704,32 @@ func fetchArticle(client *api.Client, postNumber int, printToStdout bool) {
 		os.Exit(1)
 	}
 
-	fm := types.FrontMatter{
-		Title:           post.Name,
-		Category:        post.Category,
-		Tags:            post.Tags,
-		Wip:             post.Wip,
-		RemoteUpdatedAt: post.UpdatedAt.Format(time.RFC3339),
+	// 保存先（標準出力の場合はカレントディレクトリを基準にリンクを書き換える）
+	fileName := ""
+	if !printToStdout {
+		fileName, err = postFilePath(defaults, post.Number, post.Name, post.Category)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ ディレクトリの作成に失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+		if defaults.TreeLayout() {
+			relocateExisting(post.Number, fileName)
+		}
+	}
+
+	body := post.BodyMd
+	if localLinks {
+		if body, err = workspace.NewLinks(client.TeamName()).ToLocal(body, filepath.Dir(fileName)); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  リンクの書き換えに失敗しました: %v\n", err)
+		}
 	}
+	if withAssets {
+		body = downloadAssets(post.Number, body, filepath.Dir(fileName))
+	}
+
+	fm := convert.FrontMatter(post, client.TeamName())
 
-	content, err := markdown.GenerateContent(fm, post.BodyMd)
+	content, err := markdown.GenerateContentAs(markdown.Format(defaults.FrontMatterFormat), fm, body)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ ファイル内容の生成に失敗しました: %v\n", err)
 		os.Exit(1)
@@ -456,11 +740,20 @@ func fetchArticle(client *api.Client, postNumber int, printToStdout bool) {
 		fmt.Print(string(content))
 	} else {
 		// ファイルに保存
-		fileName := fmt.Sprintf("%d-%s.md", post.Number, post.Name)
+		// 既存のファイルがあれば独自のFront Matterを残したまま書き換える
+		if existing, err := os.ReadFile(fileName); err == nil {
+			if content, err = markdown.UpdateContent(existing, fm, body); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ ファイル内容の生成に失敗しました: %v\n", err)
+				os.Exit(1)
+			}
+		}
 		if err := os.WriteFile(fileName, content, 0644); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ ファイルの書き込みに失敗しました: %v\n", err)
 			os.Exit(1)
 		}
+		if err := workspace.Track(post, fileName, content); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  同期状態の記録に失敗しました: %v\n", err)
+		}
 
 		fmt.Printf("✅ 記事をダウンロードしました: %s\n", fileName)
 		fmt.Printf("📄 ファイル名: %s\n", fileName)
@@ -482,14 +775,6 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 	}
 	fileName := cmd.Args()[0]
 
-	// ファイル名から記事番号を取得
-	postNumberStr := strings.Split(fileName, "-")[0]
-	postNumber, err := strconv.Atoi(postNumberStr)
-	if err != nil {
-		fmt.Printf("❌ 無効なファイル名です。'記事番号-タイトル.md'の形式である必要があります: %s\n", fileName)
-		os.Exit(1)
-	}
-
 	// ファイルを読み込む
 	content, err := os.ReadFile(fileName)
 	if err != nil {
@@ -502,20 +787,57 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 		fmt.Printf("❌ ファイルの解析に失敗しました: %v\n", err)
 		os.Exit(1)
 	}
+	if fm.Title == "" {
+		fmt.Println("❌ Front Matterにタイトルがありません")
+		fmt.Println("💡 ファイル先頭の --- で囲まれた部分に title を記述してください")
+		os.Exit(1)
+	}
+
+	// 記事番号はFront Matterを優先し、無ければファイル名の先頭から取得
+	postNumber := fm.Number
+	if postNumber == 0 {
+		number, ok := naming.NumberFromFileName(fileName)
+		if !ok {
+			fmt.Printf("❌ 記事番号が分かりません: %s\n", fileName)
+			fmt.Println("💡 Front Matterに number を記述するか、'記事番号-タイトル.md'の形式のファイル名にしてください")
+			os.Exit(1)
+		}
+		postNumber = number
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
 		os.Exit(1)
 	}
+	if fm.Team != "" && fm.Team != cfg.TeamName {
+		fmt.Printf("❌ このファイルはチーム %s の記事です（現在のチーム: %s）\n", fm.Team, cfg.TeamName)
+		fmt.Printf("💡 --team %s を指定してください\n", fm.Team)
+		os.Exit(1)
+	}
 	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
 
+	// メッセージの指定が無ければ設定のテンプレートから生成
+	if message == "" {
+		message = cfg.GetDefaults().CommitMessage(fm.Title, postNumber, filepath.Base(fileName), time.Now())
+	}
+
+	// ローカルの画像は元のURLに、ローカルファイルへのリンクは記事リンクに戻してからアップロードする
+	links := workspace.NewLinks(client.TeamName())
+	upload, err := convert.PrepareUpload(links, postNumber, body, filepath.Dir(fileName))
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("💡 競合を解消してから再度 update してください")
+		os.Exit(1)
+	}
+	uploadBody := upload.Text
+
 	// リモートの更新日時をチェック
 	if fm.RemoteUpdatedAt != "" {
 		remotePost, err := client.FetchPost(context.Background(), postNumber)
 		if err != nil {
 			// 記事が存在しない場合はチェックをスキップ
-			if !strings.Contains(err.Error(), "404") {
+			if !errors.Is(err, api.ErrNotFound) {
 				fmt.Printf("⚠️  リモート記事の取得に失敗しました: %v\n", err)
 			}
 		} else {
@@ -524,13 +846,36 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 				fmt.Println("⚠️  警告: リモートの記事はローカルで編集を始めてから更新されています。")
 				fmt.Printf("  リモート: %s\n", remotePost.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
 				fmt.Printf("  ローカル: %s\n", localUpdatedAt.Local().Format("2006-01-02 15:04:05"))
-				fmt.Print("このまま上書きしますか？ (y/N): ")
 
-				var confirm string
-				fmt.Scanln(&confirm)
-				if strings.ToLower(confirm) != "y" {
-					fmt.Println("🚫 更新を中止しました。")
-					os.Exit(0)
+				// 最後に取得した本文があれば3-wayマージし、無ければ上書きするか確認する
+				state, err := workspace.LoadWorkspaceState()
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					os.Exit(1)
+				}
+				base, ok := state.Base(postNumber)
+				if !ok {
+					fmt.Print("このまま上書きしますか？ (y/N): ")
+
+					var confirm string
+					fmt.Scanln(&confirm)
+					if strings.ToLower(confirm) != "y" {
+						fmt.Println("🚫 更新を中止しました。")
+						os.Exit(0)
+					}
+				} else {
+					merged, conflicts := mergeRemote(cfg.GetDefaults().MergeTool, base, uploadBody, remotePost.BodyMd, postNumber)
+					if conflicts > 0 {
+						if err := writeConflict(fileName, content, fm, remotePost, upload.Local(merged, filepath.Dir(fileName))); err != nil {
+							fmt.Printf("❌ 競合の書き込みに失敗しました: %v\n", err)
+							os.Exit(1)
+						}
+						fmt.Printf("❌ %d箇所で競合しました: %s\n", conflicts, fileName)
+						fmt.Println("💡 <<<<<<< 〜 >>>>>>> の部分を解消してから再度 update してください")
+						os.Exit(1)
+					}
+					fmt.Println("🔀 リモートの変更をマージしました")
+					uploadBody = merged
 				}
 			}
 		}
@@ -538,12 +883,12 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 
 	updateReq := types.UpdatePostBody{
 		Name:    fm.Title,
-		BodyMd:  body,
+		BodyMd:  uploadBody,
 		Message: message,
 		Wip:     fm.Wip,
 	}
 	if category != "" {
-		updateReq.Category = category
+		updateReq.Category = cfg.Project().CategoryFor(category)
 	} else {
 		updateReq.Category = fm.Category
 	}
@@ -573,20 +918,19 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 	}
 
 	updatedPost, err := client.UpdatePost(context.Background(), postNumber, updateReq)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Printf("❌ 記事 #%d はリモートで削除されています: %s\n", postNumber, fileName)
+		fmt.Println("💡 'esa-cli sync' でファイルを .trash に移動するか、'esa-cli create -f' で新しい記事として作成できます")
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Printf("❌ 記事の更新に失敗しました: %v\n", err)
 		os.Exit(1)
 	}
 
 	// ローカルファイルを更新後の内容で書き換える
-	newFm := types.FrontMatter{
-		Title:           updatedPost.Name,
-		Category:        updatedPost.Category,
-		Tags:            updatedPost.Tags,
-		Wip:             updatedPost.Wip,
-		RemoteUpdatedAt: updatedPost.UpdatedAt.Format(time.RFC3339),
-	}
-	newContent, err := markdown.GenerateContent(newFm, updatedPost.BodyMd)
+	// ローカルの画像やリンクを使っていた場合は書き換えた状態を保つ
+	newContent, err := markdown.UpdateContent(content, convert.FrontMatter(updatedPost, client.TeamName()), upload.Local(updatedPost.BodyMd, filepath.Dir(fileName)))
 	if err != nil {
 		fmt.Printf("❌ ローカルファイルの更新に失敗しました: %v\n", err)
 		os.Exit(1)
@@ -597,6 +941,20 @@ func runUpdate(cmd *pflag.FlagSet, noWip bool, category, addTags, removeTags, me
 		os.Exit(1)
 	}
 
+	// カテゴリをディレクトリにした配置では、カテゴリを変更したファイルを新しいカテゴリのディレクトリに移動する
+	if cfg.GetDefaults().TreeLayout() {
+		if moved := moveToCategory(links, postNumber, fileName, fm.Category, updatedPost.Category); moved != fileName {
+			fileName = moved
+			if newContent, err = os.ReadFile(fileName); err != nil {
+				fmt.Printf("❌ ローカルファイルの読み込みに失敗しました: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+	if err := workspace.Track(updatedPost, fileName, newContent); err != nil {
+		fmt.Printf("⚠️  同期状態の記録に失敗しました: %v\n", err)
+	}
+
 	fmt.Printf("✅ 記事を更新しました: %s\n", fileName)
 }
 
@@ -624,6 +982,12 @@ func runMove(cmd *pflag.FlagSet, category, user, query, tag, toCategory, message
 
 	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
 
+	// 移動先カテゴリの %{Year} などのプレースホルダーを展開する
+	if err := expandAll(newExpander(client), &toCategory); err != nil {
+		fmt.Printf("❌ プレースホルダーの展開に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
 	// 移動対象の記事を検索
 	// 注: 一括操作のため、最大100件（1ページ）までに制限
 	options := &api.ListPostsOptions{
@@ -707,10 +1071,16 @@ func runMove(cmd *pflag.FlagSet, category, user, query, tag, toCategory, message
 	}
 }
 
-func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bool, file string, template bool) {
-	// 設定の読み込み（テンプレートモードでない場合のみ必要）
-	if !template {
-		cfg, err := config.Load()
+func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bool, file string, template bool, edit bool, fromTemplate string) {
+	if file != "" && fromTemplate != "" {
+		fmt.Println("❌ --file と --from-template は同時に指定できません")
+		os.Exit(1)
+	}
+
+	// 設定の読み込み（テンプレートモードでesa.ioのテンプレートも使わない場合は、設定が無くてもよい）
+	needsAPI := !template || fromTemplate != ""
+	cfg, err := config.Load()
+	if needsAPI {
 		if err != nil {
 			fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
 			fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
@@ -730,7 +1100,7 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 	}
 
 	// 対話形式での入力（タイトルが指定されていない場合）
-	if title == "" && file == "" {
+	if title == "" && file == "" && fromTemplate == "" {
 		if template {
 			fmt.Println("📝 新しい記事のテンプレートを作成します")
 		} else {
@@ -745,11 +1115,24 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 	}
 
 	var client *api.Client
-	if !template {
-		cfg, _ := config.Load()
+	if needsAPI {
 		client = newAPIClient(cfg.TeamName, cfg.AccessToken)
 	}
 
+	// フラグが指定されていない項目は設定（選択中のプロファイル）のデフォルトを使う
+	defaults := config.LoadDefaults()
+	if cfg != nil {
+		defaults = cfg.GetDefaults()
+	}
+	if category == "" {
+		category = defaults.Category
+	} else if project, _ := config.LoadProjectConfig(); project != nil {
+		category = project.CategoryFor(category)
+	}
+	if !cmd.Changed("wip") && defaults.Wip != nil {
+		wip = *defaults.Wip
+	}
+
 	// タグの処理
 	var tagList []string
 	if tags != "" {
@@ -757,6 +1140,8 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 		for i, tag := range tagList {
 			tagList[i] = strings.TrimSpace(tag)
 		}
+	} else {
+		tagList = defaults.Tags
 	}
 
 	// 記事作成リクエストの作成
@@ -798,6 +1183,52 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 		createBody.BodyMd = body
 	}
 
+	// esa.ioのテンプレート記事から作成する場合
+	expander := newExpander(client)
+	if fromTemplate != "" {
+		tmpl, err := templates.Find(context.Background(), client, fromTemplate)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			fmt.Println("💡 'esa-cli templates' で使えるテンプレートを確認できます")
+			os.Exit(1)
+		}
+		fmt.Printf("📋 テンプレート: [%d] %s\n", tmpl.Number, tmpl.FullName)
+
+		if template {
+			// ローカルの下書きにはプレースホルダーを展開したテンプレートの内容を書き込む
+			fm, body, err := templates.Materialize(tmpl, expander)
+			if err != nil {
+				fmt.Printf("❌ プレースホルダーの展開に失敗しました: %v\n", err)
+				os.Exit(1)
+			}
+			if title == "" {
+				createBody.Name = fm.Title
+			}
+			if !cmd.Changed("category") {
+				createBody.Category = fm.Category
+			}
+			if tags == "" {
+				createBody.Tags = fm.Tags
+			}
+			createBody.BodyMd = body
+		} else {
+			// 指定されなかった項目はesa.ioがテンプレートの内容で埋める
+			createBody.TemplatePostID = tmpl.Number
+			if !cmd.Changed("category") {
+				createBody.Category = ""
+			}
+			if tags == "" {
+				createBody.Tags = nil
+			}
+		}
+	}
+
+	// タイトル・カテゴリの %{Year} などのプレースホルダーを展開する
+	if err := expandAll(expander, &createBody.Name, &createBody.Category); err != nil {
+		fmt.Printf("❌ プレースホルダーの展開に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
 	if template {
 		// テンプレートモード: ローカルファイルのみ生成
 		fm := types.FrontMatter{
@@ -807,15 +1238,24 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 			Wip:      createBody.Wip,
 		}
 
-		content, err := markdown.GenerateContent(fm, createBody.BodyMd)
+		content, err := markdown.GenerateContentAs(markdown.Format(defaults.FrontMatterFormat), fm, createBody.BodyMd)
 		if err != nil {
 			fmt.Printf("❌ ファイル内容の生成に失敗しました: %v\n", err)
 			os.Exit(1)
 		}
 
 		// ファイル名を生成（記事番号がないので、タイトルベース）
-		fileName := sanitizeFilename(title)
-		fileName = fmt.Sprintf("draft-%s.md", fileName)
+		// 同じタイトルの下書きがあれば上書きしないよう番号を付ける
+		fileName := naming.Unique(filepath.Join(defaults.OutputDir, "draft-"+naming.Slug(createBody.Name)+".md"), func(path string) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		})
+		if defaults.OutputDir != "" {
+			if err := os.MkdirAll(defaults.OutputDir, 0755); err != nil {
+				fmt.Printf("❌ ディレクトリの作成に失敗しました: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		if err := os.WriteFile(fileName, content, 0644); err != nil {
 			fmt.Printf("❌ ファイルの書き込みに失敗しました: %v\n", err)
@@ -831,9 +1271,31 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 			fmt.Printf("🏷️  タグ: %s\n", strings.Join(createBody.Tags, ", "))
 		}
 		fmt.Printf("💡 編集後、'esa-cli create -f %s' でesa.ioにアップロードできます\n", fileName)
+		if edit {
+			if err := openInEditor(defaults.Editor, fileName); err != nil {
+				fmt.Printf("⚠️  エディタを開けませんでした: %v\n", err)
+			}
+		}
 		return
 	}
 
+	if createBody.Message == "" {
+		sourceFile := ""
+		if file != "" {
+			sourceFile = filepath.Base(file)
+		}
+		createBody.Message = defaults.CommitMessage(createBody.Name, 0, sourceFile, time.Now())
+	}
+
+	// ローカルファイルへのリンクは記事リンクに戻してからアップロードする
+	upload, err := convert.PrepareUpload(workspace.NewLinks(client.TeamName()), 0, createBody.BodyMd, filepath.Dir(file))
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("💡 競合を解消してから再度 create してください")
+		os.Exit(1)
+	}
+	createBody.BodyMd = upload.Text
+
 	// 通常モード: esa.ioに記事を作成
 	post, err := client.CreatePost(context.Background(), createBody)
 	if err != nil {
@@ -842,47 +1304,33 @@ func runCreate(cmd *pflag.FlagSet, title, category, tags, message string, wip bo
 	}
 
 	// 作成された記事をローカルファイルとして保存
-	fm := types.FrontMatter{
-		Title:           post.Name,
-		Category:        post.Category,
-		Tags:            post.Tags,
-		Wip:             post.Wip,
-		RemoteUpdatedAt: post.UpdatedAt.Format(time.RFC3339),
+	fm := convert.FrontMatter(post, client.TeamName())
+
+	fileName, err := postFilePath(defaults, post.Number, post.Name, post.Category)
+	if err != nil {
+		fmt.Printf("❌ ディレクトリの作成に失敗しました: %v\n", err)
+		os.Exit(1)
 	}
 
-	content, err := markdown.GenerateContent(fm, post.BodyMd)
+	localBody := upload.Local(post.BodyMd, filepath.Dir(fileName))
+	content, err := markdown.GenerateContentAs(markdown.Format(defaults.FrontMatterFormat), fm, localBody)
 	if err != nil {
 		fmt.Printf("❌ ファイル内容の生成に失敗しました: %v\n", err)
 		os.Exit(1)
 	}
-
-	fileName := fmt.Sprintf("%d-%s.md", post.Number, post.Name)
 	if err := os.WriteFile(fileName, content, 0644); err != nil {
 		fmt.Printf("❌ ファイルの書き込みに失敗しました: %v\n", err)
 		os.Exit(1)
 	}
+	if err := workspace.Track(post, fileName, content); err != nil {
+		fmt.Printf("⚠️  同期状態の記録に失敗しました: %v\n", err)
+	}
 
 	fmt.Printf("✅ 新しい記事が作成されました: %s\n", post.FullName)
 	fmt.Printf("📄 ローカルファイル: %s\n", fileName)
-}
-
-// sanitizeFilename ファイル名に使用できない文字を置換
-func sanitizeFilename(name string) string {
-	replacements := map[string]string{
-		"/": "-", "\\": "-", ":": "-", "*": "-",
-		"?": "-", "\"": "-", "<": "-", ">": "-", "|": "-",
-		" ": "-",
-	}
-
-	result := name
-	for old, new := range replacements {
-		result = strings.ReplaceAll(result, old, new)
-	}
-
-	// 長すぎる場合は切り詰め
-	if len(result) > 100 {
-		result = result[:100]
+	if edit {
+		if err := openInEditor(defaults.Editor, fileName); err != nil {
+			fmt.Printf("⚠️  エディタを開けませんでした: %v\n", err)
+		}
 	}
-
-	return result
 }
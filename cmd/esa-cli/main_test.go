@@ -134,6 +134,9 @@ func TestFetch(t *testing.T) {
 	config.ConfigFile = configPath
 	defer func() { config.ConfigFile = origConfigFile }()
 
+	// 取得した記事は一時ディレクトリに保存する
+	chdir(t, tmpDir)
+
 	// モッククライアントを作成
 	mockClient := mock.NewMockHTTPClient()
 
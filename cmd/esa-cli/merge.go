@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// mergeRemote ベース・ローカル・リモートの本文を3-wayマージする
+// 競合した場合はマージツール（設定されていれば）で解消し、解消できなければ競合マーカー入りの本文と競合の数を返す
+func mergeRemote(mergeTool, base, local, remote string, number int) (string, int) {
+	merged, conflicts := diff.Merge(base, local, remote, "ローカル", fmt.Sprintf("リモート (#%d)", number))
+	if conflicts == 0 || mergeTool == "" {
+		return merged, conflicts
+	}
+
+	resolved, err := runMergeTool(mergeTool, base, local, remote, merged)
+	if err != nil {
+		fmt.Printf("⚠️  マージツールの実行に失敗しました: %v\n", err)
+		return merged, conflicts
+	}
+	if diff.HasConflictMarkers(resolved) {
+		fmt.Println("⚠️  マージツールの実行後も競合マーカーが残っています")
+		return merged, conflicts
+	}
+	return resolved, 0
+}
+
+// runMergeTool マージツールで競合を解消する
+// git の mergetool と同じく、各版を一時ファイルに書き出して $BASE $LOCAL $REMOTE $MERGED で渡す
+// $MERGED には競合マーカー入りの本文を書いておき、ツールの終了後に読み込む
+func runMergeTool(mergeTool, base, local, remote, merged string) (string, error) {
+	dir, err := os.MkdirTemp("", "esa-cli-merge-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"BASE": base, "LOCAL": local, "REMOTE": remote, "MERGED": merged}
+	env := os.Environ()
+	for name, body := range files {
+		path := filepath.Join(dir, name+".md")
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			return "", err
+		}
+		env = append(env, name+"="+path)
+	}
+
+	cmd := exec.Command("sh", "-c", mergeTool)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "MERGED.md"))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// writeConflict 競合マーカー入りの本文をローカルファイルに書き込む
+// remote_updated_at・ベース・同期状態はリモートの版に進めるので、競合を解消すればそのまま update できる
+// 解消するまでは同期状態でも競合として扱う
+func writeConflict(fileName string, content []byte, fm types.FrontMatter, remote *types.Post, body string) error {
+	fm.RemoteUpdatedAt = remote.UpdatedAt.Format(time.RFC3339)
+	newContent, err := markdown.UpdateContent(content, fm, body)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fileName, newContent, 0644); err != nil {
+		return err
+	}
+
+	state, err := workspace.LoadWorkspaceState()
+	if err != nil {
+		return err
+	}
+	// 同期状態もリモートの版に進め、競合を解消するまでは競合として記録する
+	state.RecordConflict(remote, fileName, newContent)
+	return state.SaveTracked()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeRemote(t *testing.T) {
+	base := "一\n二\n三\n"
+	local := "一\n二（ローカル）\n三\n"
+	remote := "一\n二（リモート）\n三\n"
+
+	tests := []struct {
+		name          string
+		mergeTool     string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:          "正常系：マージツールが未設定なら競合マーカーを返す",
+			want:          "<<<<<<< ローカル\n",
+			wantConflicts: 1,
+		},
+		{
+			name:      "正常系：マージツールで解消する",
+			mergeTool: `cp "$REMOTE" "$MERGED"`,
+			want:      remote,
+		},
+		{
+			name:          "異常系：マージツールが失敗したら競合のまま",
+			mergeTool:     "exit 1",
+			want:          "<<<<<<< ローカル\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "異常系：競合マーカーが残っていたら競合のまま",
+			mergeTool:     "true",
+			want:          "<<<<<<< ローカル\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := mergeRemote(tt.mergeTool, base, local, remote, 1)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("mergeRemote() = %q, want %q を含む", got, tt.want)
+			}
+			if conflicts != tt.wantConflicts {
+				t.Errorf("mergeRemote() conflicts = %d, want %d", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/naming"
+	"github.com/spf13/pflag"
+)
+
+// runMigrate 既存のワークスペースのFront Matterに記事番号とチーム名を書き込む
+func runMigrate(cmd *pflag.FlagSet, dryRun bool) {
+	dir := "."
+	if len(cmd.Args()) > 0 {
+		dir = cmd.Args()[0]
+	}
+
+	// チーム名は設定から取得（設定が無ければ記事番号のみ書き込む）
+	team := ""
+	if cfg, err := config.Load(); err == nil {
+		team = cfg.TeamName
+	}
+
+	fmt.Println("🔧 Front Matterに記事番号を書き込みます...")
+	fmt.Printf("   ディレクトリ: %s\n", dir)
+	if dryRun {
+		fmt.Println("   （--dry-run: ファイルは変更しません）")
+	}
+	fmt.Println()
+
+	migrated := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// .git や .esa-cli などの隠しディレクトリはスキップ
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		changed, err := migrateFile(path, team, dryRun)
+		if err != nil {
+			fmt.Printf("   ⚠️  %s: %v\n", path, err)
+			return nil
+		}
+		if changed {
+			fmt.Printf("   ✅ %s\n", path)
+			migrated++
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("❌ ファイルの検索に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	if migrated == 0 {
+		fmt.Println("📭 移行が必要なファイルはありませんでした")
+		return
+	}
+	fmt.Printf("✅ 移行完了 (%d件)\n", migrated)
+}
+
+// migrateFile Front Matterに記事番号・チーム名が無ければファイル名から補完する
+// 変更が必要だったかどうかを返す
+func migrateFile(path, team string, dryRun bool) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	doc, err := markdown.Parse(content)
+	if err != nil {
+		return false, err
+	}
+	if !doc.HasFrontMatter {
+		return false, nil
+	}
+
+	changed := false
+	if _, ok := doc.Get("number"); !ok {
+		number, ok := naming.NumberFromFileName(path)
+		if !ok {
+			// 記事番号の分からないファイル（未投稿の下書きなど）は対象外
+			return false, nil
+		}
+		if err := doc.Set("number", number); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	if _, ok := doc.Get("team"); !ok && team != "" {
+		if err := doc.Set("team", team); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	if !changed || dryRun {
+		return changed, nil
+	}
+	return true, os.WriteFile(path, doc.Bytes(), 0644)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		content     string
+		team        string
+		dryRun      bool
+		wantChanged bool
+		want        string
+	}{
+		{
+			name:        "正常系：ファイル名から記事番号とチーム名を補完",
+			fileName:    "123-テスト.md",
+			content:     "---\ntitle: テスト\nowner: alice # 担当者\n---\n\n本文\n",
+			team:        "my-team",
+			wantChanged: true,
+			want:        "---\ntitle: テスト\nowner: alice # 担当者\nnumber: 123\nteam: my-team\n---\n\n本文\n",
+		},
+		{
+			name:        "正常系：記事番号があればファイル名に関係なく変更しない",
+			fileName:    "設計メモ.md",
+			content:     "---\ntitle: テスト\nnumber: 45\nteam: my-team\n---\n\n本文\n",
+			team:        "my-team",
+			wantChanged: false,
+			want:        "---\ntitle: テスト\nnumber: 45\nteam: my-team\n---\n\n本文\n",
+		},
+		{
+			name:        "正常系：dry-runではファイルを書き換えない",
+			fileName:    "7-テスト.md",
+			content:     "---\ntitle: テスト\n---\n\n本文\n",
+			dryRun:      true,
+			wantChanged: true,
+			want:        "---\ntitle: テスト\n---\n\n本文\n",
+		},
+		{
+			name:        "エッジケース：記事番号の分からないファイルは対象外",
+			fileName:    "下書き.md",
+			content:     "---\ntitle: テスト\n---\n\n本文\n",
+			team:        "my-team",
+			wantChanged: false,
+			want:        "---\ntitle: テスト\n---\n\n本文\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			changed, err := migrateFile(path, tt.team, tt.dryRun)
+			if err != nil {
+				t.Fatalf("migrateFile() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("migrateFile() = %v, want %v", changed, tt.wantChanged)
+			}
+
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("ファイルの内容 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
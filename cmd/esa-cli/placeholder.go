@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/placeholder"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// userCacheTTL 現在のユーザー情報をキャッシュする期間
+const userCacheTTL = 24 * time.Hour
+
+// userCacheEntry チームごとのユーザー情報のキャッシュ
+type userCacheEntry struct {
+	User      types.User `json:"user"`
+	FetchedAt time.Time  `json:"fetched_at"`
+}
+
+// userCachePath ユーザー情報のキャッシュファイルのパス
+func userCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "esa-cli", "users.json")
+}
+
+// newExpander プレースホルダーを展開するExpanderを作成する
+// client が nil の場合は、%{me}・%{name} を展開するときに設定からクライアントを作成する
+func newExpander(client *api.Client) *placeholder.Expander {
+	return &placeholder.Expander{
+		Now: time.Now(),
+		User: func() (*types.User, error) {
+			if client == nil {
+				cfg, err := config.Load()
+				if err != nil || cfg.AccessToken == "" || cfg.TeamName == "" {
+					return nil, fmt.Errorf("設定が完了していません（'esa-cli setup' で初期設定を行ってください）")
+				}
+				client = newAPIClient(cfg.TeamName, cfg.AccessToken)
+			}
+			return currentUser(client)
+		},
+	}
+}
+
+// currentUser 現在のユーザー情報を取得する（キャッシュがあればAPIを呼ばない）
+func currentUser(client *api.Client) (*types.User, error) {
+	path := userCachePath()
+	cache := map[string]userCacheEntry{}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			_ = json.Unmarshal(data, &cache)
+		}
+	}
+	if entry, ok := cache[client.TeamName()]; ok && time.Since(entry.FetchedAt) < userCacheTTL {
+		return &entry.User, nil
+	}
+
+	user, err := client.GetUser(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// キャッシュの保存に失敗しても展開は続ける
+	if path != "" {
+		cache[client.TeamName()] = userCacheEntry{User: *user, FetchedAt: time.Now()}
+		if data, err := json.MarshalIndent(cache, "", "  "); err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+				_ = os.WriteFile(path, data, 0600)
+			}
+		}
+	}
+	return user, nil
+}
+
+// expandAll 文字列中のプレースホルダーをまとめて展開する
+func expandAll(expander *placeholder.Expander, values ...*string) error {
+	for _, v := range values {
+		expanded, err := expander.Expand(*v)
+		if err != nil {
+			return err
+		}
+		*v = expanded
+	}
+	return nil
+}
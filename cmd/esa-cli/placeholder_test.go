@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/testutil"
+)
+
+func TestCurrentUser_Cache(t *testing.T) {
+	// キャッシュの保存先をテスト用の一時ディレクトリにする
+	tmpDir := testutil.CreateTempDir(t)
+	t.Setenv("XDG_CACHE_HOME", tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	mockClient := mock.NewMockHTTPClient()
+	mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+		return testutil.CreateMockResponse(t, http.StatusOK, `{"id": 1, "name": "山田 太郎", "screen_name": "yamada"}`), nil
+	})
+	client := api.NewClient("test-team", "token", mockClient)
+
+	for i := 0; i < 2; i++ {
+		user, err := currentUser(client)
+		if err != nil {
+			t.Fatalf("currentUser() error = %v", err)
+		}
+		if user.ScreenName != "yamada" {
+			t.Errorf("currentUser() = %+v", user)
+		}
+	}
+	if got := len(mockClient.GetRequests()); got != 1 {
+		t.Errorf("APIの呼び出し回数 = %d, want 1", got)
+	}
+
+	// 別のチームはキャッシュを共有しない
+	if _, err := currentUser(api.NewClient("other-team", "token", mockClient)); err != nil {
+		t.Fatalf("currentUser() error = %v", err)
+	}
+	if got := len(mockClient.GetRequests()); got != 2 {
+		t.Errorf("APIの呼び出し回数 = %d, want 2", got)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+
+	"github.com/shellme/esa-cli/internal/mac"
+	"github.com/shellme/esa-cli/internal/preview"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/spf13/pflag"
+)
+
+// runPreview Markdownファイルをローカルのサーバーでプレビューする
+func runPreview(cmd *pflag.FlagSet, port int, open bool) {
+	if len(cmd.Args()) < 1 {
+		fmt.Println("❌ ファイル名を指定してください")
+		fmt.Println("💡 使用方法: esa-cli preview <ファイル名>")
+		os.Exit(1)
+	}
+	fileName := cmd.Args()[0]
+
+	root, err := workspace.Root()
+	if err != nil {
+		fmt.Printf("❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 外部から接続できないよう localhost でのみ待ち受ける
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		fmt.Printf("❌ サーバーを起動できません: %v\n", err)
+		os.Exit(1)
+	}
+	server, err := preview.New(fileName, root, listener.Addr().String())
+	if err != nil {
+		fmt.Printf("❌ ファイルを開けません: %v\n", err)
+		os.Exit(1)
+	}
+	pageURL := (&url.URL{Scheme: "http", Host: listener.Addr().String(), Path: server.URLPath()}).String()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go server.Watch(ctx)
+
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	fmt.Printf("👀 プレビュー: %s\n", pageURL)
+	fmt.Println("💡 ファイルを保存するとブラウザが自動で再読み込みされます（Ctrl+Cで終了）")
+	if open {
+		if err := mac.OpenInFinder(pageURL); err != nil {
+			fmt.Printf("⚠️  ブラウザを開けませんでした: %v\n", err)
+		}
+	}
+
+	if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		fmt.Printf("❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/spf13/pflag"
+)
+
+// addGlobalFlags 全コマンド共通のフラグを登録
+func addGlobalFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&config.SelectedProfile, "profile", "", "使用するプロファイル")
+	fs.StringVar(&config.SelectedProfile, "team", "", "使用するプロファイル（--profileの別名）")
+}
+
+func runProfile(cmd *pflag.FlagSet, teamName, token, credentialHelper string) {
+	if len(cmd.Args()) < 1 {
+		fmt.Println("❌ サブコマンドを指定してください (list|use|add|remove)")
+		fmt.Println("💡 使用例: esa-cli profile list")
+		os.Exit(1)
+	}
+
+	subcommand := cmd.Args()[0]
+	name := ""
+	if len(cmd.Args()) > 1 {
+		name = cmd.Args()[1]
+	}
+
+	switch subcommand {
+	case "list":
+		cfg, err := config.ListProfiles()
+		if err != nil {
+			fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+			fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+			os.Exit(1)
+		}
+		if len(cfg.Profiles) == 0 {
+			fmt.Println("📭 プロファイルが登録されていません")
+			return
+		}
+		fmt.Printf("👥 プロファイル一覧 (%d件):\n", len(cfg.Profiles))
+		for _, n := range cfg.ProfileNames() {
+			mark := " "
+			if n == cfg.DefaultProfile {
+				mark = "*"
+			}
+			fmt.Printf("  %s %s (%s.esa.io)\n", mark, n, cfg.Profiles[n].TeamName)
+		}
+	case "use":
+		if name == "" {
+			fmt.Println("❌ プロファイル名を指定してください")
+			fmt.Println("💡 使用例: esa-cli profile use work")
+			os.Exit(1)
+		}
+		if err := config.UseProfile(name); err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ デフォルトのプロファイルを %s に切り替えました\n", name)
+	case "add":
+		if name == "" {
+			fmt.Println("❌ プロファイル名を指定してください")
+			fmt.Println("💡 使用例: esa-cli profile add work --team-name my-company --token xxxx")
+			os.Exit(1)
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		if teamName == "" {
+			fmt.Print("チーム名（サブドメイン）を入力: ")
+			if scanner.Scan() {
+				teamName = strings.TrimSpace(scanner.Text())
+			}
+		}
+		// クレデンシャルヘルパーを使う場合はトークンの入力を省略できる
+		if token == "" && credentialHelper == "" {
+			fmt.Print("アクセストークンを入力: ")
+			if scanner.Scan() {
+				token = strings.TrimSpace(scanner.Text())
+			}
+		}
+		if err := config.AddProfile(name, teamName, token, credentialHelper); err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ プロファイルを追加しました: %s (%s.esa.io)\n", name, teamName)
+	case "remove":
+		if name == "" {
+			fmt.Println("❌ プロファイル名を指定してください")
+			fmt.Println("💡 使用例: esa-cli profile remove work")
+			os.Exit(1)
+		}
+		if err := config.RemoveProfile(name); err != nil {
+			fmt.Printf("❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ プロファイルを削除しました: %s\n", name)
+	default:
+		fmt.Printf("❌ 不明なサブコマンド: %s\n", subcommand)
+		fmt.Println("💡 使用できるサブコマンド: list, use, add, remove")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// runPull 前回の pull 以降にリモートで変更された記事だけをワークスペースに取り込む
+func runPull(cmd *pflag.FlagSet, category string, full, noMerge, dryRun bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Println("❌ 設定が完了していません")
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+
+	project := cfg.Project()
+	category = project.CategoryFor(category)
+	if category == "" {
+		fmt.Println("❌ 取得するカテゴリを指定してください")
+		fmt.Println("💡 -c オプションか、.esa-cli.yml の category で指定できます")
+		os.Exit(1)
+	}
+
+	root, err := workspace.Root()
+	if err != nil {
+		fmt.Printf("❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("⬇️  リモートの変更を取り込みます...")
+	fmt.Printf("   カテゴリ: %s\n", category)
+	fmt.Printf("   ディレクトリ: %s\n", root)
+	since := state.LastPulled(category)
+	if full {
+		since = time.Time{}
+	}
+	if !since.IsZero() {
+		fmt.Printf("   前回の取得: %s\n", since.Local().Format("2006-01-02 15:04:05"))
+	}
+	if dryRun {
+		fmt.Println("   （--dry-run: 変更は行いません）")
+	}
+	fmt.Println()
+
+	startedAt := time.Now()
+	remote, err := searchCategoryPosts(client, category, updatedSince(since))
+	if err != nil {
+		fmt.Printf("❌ 記事の取得に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+	local, err := workspace.ScanFiles(root, project.Ignored)
+	if err != nil {
+		fmt.Printf("❌ ファイルの読み込みに失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := &puller{
+		syncer: syncer{
+			client:   client,
+			state:    state,
+			defaults: cfg.GetDefaults(),
+			category: category,
+			dryRun:   dryRun,
+		},
+		noMerge: noMerge,
+		local:   map[int]string{},
+	}
+	for _, f := range local {
+		if f.Number > 0 {
+			p.local[f.Number] = f.Path
+		}
+	}
+
+	// 取り込めなかった記事は次回も検索にかかるよう、記録する時刻をその記事の更新日時までに留める
+	mark := startedAt
+	for _, post := range remote {
+		if !p.apply(post) && post.UpdatedAt.Before(mark) {
+			mark = post.UpdatedAt
+		}
+	}
+
+	if !dryRun {
+		state.SetLastPull(category, mark)
+		if err := state.Save(); err != nil {
+			fmt.Printf("❌ 同期状態の保存に失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ 取得完了: 取得 %d件 / マージ %d件 / 変更なし %d件 / スキップ %d件\n", p.pulled, p.merged, p.unchanged, p.skipped)
+	if p.conflicts > 0 || p.failed > 0 {
+		fmt.Printf("⚠️  競合 %d件 / 失敗 %d件\n", p.conflicts, p.failed)
+		os.Exit(1)
+	}
+}
+
+// updatedSince 前回の pull 以降に更新された記事の検索条件
+// esa の updated: は日付単位のため、時差や時計のずれを考えて1日前から検索し、リビジョン番号で絞り込む
+func updatedSince(since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return "updated:>" + since.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// puller リモートの変更をローカルに取り込む（ローカルの変更はアップロードしない）
+type puller struct {
+	syncer
+	noMerge bool
+	// local 記事番号 → ワークスペース内のファイル
+	local map[int]string
+
+	merged, unchanged, skipped int
+}
+
+// apply 記事をローカルに取り込む
+// 取り込めなかった（スキップ・失敗した）場合は false を返す
+func (p *puller) apply(post *types.Post) bool {
+	entry, tracked := p.state.Entry(post.Number)
+	if tracked && entry.Revision > 0 && entry.Revision == post.RevisionNumber {
+		p.unchanged++
+		return true
+	}
+
+	path := p.local[post.Number]
+	if tracked {
+		path = p.state.Abs(entry)
+	}
+	if path == "" {
+		path = workspace.PostPath(filepath.Join(p.state.Root(), p.defaults.FileName(post.Number, post.Name, post.Category)), post.Number)
+		fmt.Printf("🆕 取得: [%d] %s → %s\n", post.Number, post.FullName, p.state.Rel(path))
+		return p.count(p.pull(post, path), &p.pulled)
+	}
+
+	rel := p.state.Rel(path)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		fmt.Printf("⚠️  ローカルファイルがありません: [%d] %s\n", post.Number, rel)
+		p.skipped++
+		return false
+	}
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		p.failed++
+		return false
+	}
+
+	if !tracked && fetchedAt(content).Equal(post.UpdatedAt) {
+		// sync・pull 以前に取得したファイルで、取得したときからリモートが変わっていない
+		p.unchanged++
+		return true
+	}
+
+	modified, known := p.localModified(post, path, content)
+	switch {
+	case !known:
+		fmt.Printf("⚠️  競合: %s（同期の記録が無く、ローカルとリモートの内容が異なります）\n", rel)
+		p.conflicts++
+		return false
+	case modified && p.noMerge:
+		fmt.Printf("⏭️  スキップ: %s（ローカルで変更されています）\n", rel)
+		p.skipped++
+		return false
+	}
+
+	// カテゴリが変わっていれば、取り込む前に新しいカテゴリのディレクトリに移動する
+	if moved := p.relocate(post, path); moved != path {
+		if content, err = os.ReadFile(moved); err != nil {
+			fmt.Printf("❌ %s: %v\n", p.state.Rel(moved), err)
+			p.failed++
+			return false
+		}
+		path, rel = moved, p.state.Rel(moved)
+	}
+	if !modified {
+		fmt.Printf("⬇️  取得: [%d] %s → %s\n", post.Number, post.FullName, rel)
+		return p.count(p.pull(post, path), &p.pulled)
+	}
+	return p.merge(post, path, content)
+}
+
+// fetchedAt ファイルを取得したときのリモートの更新日時（Front Matterの remote_updated_at）
+func fetchedAt(content []byte) time.Time {
+	fm, _, err := markdown.ParseContent(content)
+	if err != nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, fm.RemoteUpdatedAt)
+	return t
+}
+
+// localModified 最後に取得してからローカルファイルが変更されたか
+// 同期状態が無いファイルは、最後に取得した本文（ベース）と比較する。どちらも無ければ known は false
+func (p *puller) localModified(post *types.Post, path string, content []byte) (modified, known bool) {
+	if e, ok := p.state.Entry(post.Number); ok {
+		return e.Hash != workspace.Hash(content), true
+	}
+	_, body, err := markdown.ParseContent(content)
+	if err != nil {
+		return false, false
+	}
+	upload := convert.ToUpload(p.linker(), post.Number, body, filepath.Dir(path)).Text
+	base, ok := p.state.Base(post.Number)
+	if !ok {
+		return false, false
+	}
+	return diff.Changed(diff.Lines(base, upload)), true
+}
+
+// merge ローカルの変更とリモートの変更を3-wayマージしてローカルファイルに書き込む
+// マージした結果はアップロードせず、ローカルの変更として残す
+func (p *puller) merge(post *types.Post, path string, content []byte) bool {
+	rel := p.state.Rel(path)
+	base, ok := p.state.Base(post.Number)
+	if !ok {
+		fmt.Printf("⚠️  競合: %s（ローカルとリモートの両方が変更されていて、マージのベースがありません）\n", rel)
+		p.conflicts++
+		return false
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		p.failed++
+		return false
+	}
+
+	// フロントマターも本文と同じく項目ごとにベースとマージする
+	var baseMeta *workspace.Meta
+	if e, ok := p.state.Entry(post.Number); ok {
+		baseMeta = e.Meta
+	}
+	meta, dropped := workspace.MergeMeta(baseMeta, workspace.FrontMatterMeta(fm), workspace.PostMeta(post))
+	warnDropped := func() {
+		for _, field := range dropped {
+			fmt.Printf("⚠️  %s: リモートでの%sの変更は反映せず、ローカルの値を残しました\n", rel, field)
+		}
+	}
+
+	upload := convert.ToUpload(p.linker(), post.Number, body, filepath.Dir(path))
+	if p.dryRun {
+		_, conflicts := diff.Merge(base, upload.Text, post.BodyMd, "", "")
+		if conflicts > 0 {
+			fmt.Printf("⚠️  競合: %s（%d箇所）\n", rel, conflicts)
+			p.conflicts++
+		} else {
+			fmt.Printf("🔀 マージ: [%d] %s → %s\n", post.Number, post.FullName, rel)
+			p.merged++
+		}
+		warnDropped()
+		return true
+	}
+
+	merged, conflicts := mergeRemote(p.defaults.MergeTool, base, upload.Text, post.BodyMd, post.Number)
+	meta.Apply(&fm)
+	fm.RemoteUpdatedAt = post.UpdatedAt.Format(time.RFC3339)
+	newContent, err := markdown.UpdateContent(content, fm, upload.Local(merged, filepath.Dir(path)))
+	if err == nil {
+		err = os.WriteFile(path, newContent, 0644)
+	}
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		p.failed++
+		return false
+	}
+	if conflicts > 0 {
+		// 競合を解消するまではローカルの変更として扱わない
+		p.state.RecordConflict(post, path, newContent)
+		fmt.Printf("⚠️  競合: %s（%d箇所。<<<<<<< 〜 >>>>>>> を解消してください）\n", rel, conflicts)
+		p.conflicts++
+		warnDropped()
+		return true
+	}
+	p.state.Rebase(post, path)
+	fmt.Printf("🔀 マージ: [%d] %s → %s\n", post.Number, post.FullName, rel)
+	p.merged++
+	warnDropped()
+	return true
+}
+
+func (p *puller) count(err error, n *int) bool {
+	p.do(err, n)
+	return err == nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestUpdatedSince(t *testing.T) {
+	tests := []struct {
+		name  string
+		since time.Time
+		want  string
+	}{
+		{
+			name:  "正常系：前日以降を検索する",
+			since: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+			want:  "updated:>2025-02-28",
+		},
+		{
+			name: "エッジケース：初回はすべて",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updatedSince(tt.since); got != tt.want {
+				t.Errorf("updatedSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPuller_Apply(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	base := "# 手順\n1. 準備\n2. 実行\n3. 確認\n"
+
+	tests := []struct {
+		name      string
+		local     string // 空ならローカルファイルを作らない
+		modified  bool   // 同期した後にローカルを変更したか
+		remote    types.Post
+		noMerge   bool
+		want      string
+		wantApply bool
+	}{
+		{
+			name:      "正常系：リビジョンが同じ記事は取得しない",
+			local:     base,
+			remote:    types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: base, RevisionNumber: 1, UpdatedAt: t0},
+			want:      base,
+			wantApply: true,
+		},
+		{
+			name:      "正常系：ローカルが未変更ならリモートで上書きする",
+			local:     base,
+			remote:    types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: base + "4. 片付け\n", RevisionNumber: 2, UpdatedAt: t1},
+			want:      base + "4. 片付け\n",
+			wantApply: true,
+		},
+		{
+			name:      "正常系：ローカルの変更とリモートの変更をマージする",
+			local:     "# 手順\n1. 準備する\n2. 実行\n3. 確認\n",
+			modified:  true,
+			remote:    types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: base + "4. 片付け\n", RevisionNumber: 2, UpdatedAt: t1},
+			want:      "# 手順\n1. 準備する\n2. 実行\n3. 確認\n4. 片付け\n",
+			wantApply: true,
+		},
+		{
+			name:     "正常系：--no-merge ならローカルの変更を残してスキップする",
+			local:    "# 手順\n1. 準備する\n2. 実行\n3. 確認\n",
+			modified: true,
+			remote:   types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: base + "4. 片付け\n", RevisionNumber: 2, UpdatedAt: t1},
+			noMerge:  true,
+			want:     "# 手順\n1. 準備する\n2. 実行\n3. 確認\n",
+		},
+		{
+			name:      "正常系：新しい記事を取得する",
+			remote:    types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: base, RevisionNumber: 1, UpdatedAt: t1},
+			want:      base,
+			wantApply: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, "1-手順.md")
+
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &puller{
+				syncer: syncer{
+					client: api.NewClient("test-team", "token", mock.NewMockHTTPClient()),
+					state:  state,
+				},
+				noMerge: tt.noMerge,
+				local:   map[int]string{},
+			}
+			if tt.local != "" {
+				synced := "---\ntitle: 手順\nnumber: 1\n---\n" + base
+				if err := os.WriteFile(path, []byte(synced), 0644); err != nil {
+					t.Fatal(err)
+				}
+				state.Track(&types.Post{Number: 1, BodyMd: base, RevisionNumber: 1, UpdatedAt: t0}, path, []byte(synced))
+				if tt.modified {
+					if err := os.WriteFile(path, []byte("---\ntitle: 手順\nnumber: 1\n---\n"+tt.local), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				p.local[1] = path
+			}
+
+			if got := p.apply(&tt.remote); got != tt.wantApply {
+				t.Errorf("apply() = %v, want %v", got, tt.wantApply)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(string(content), tt.want) {
+				t.Errorf("ファイルの内容 = %q, want 本文 %q", content, tt.want)
+			}
+
+			// マージした結果はローカルの変更として残る
+			entry, _ := state.Entry(1)
+			if entry.Revision != tt.remote.RevisionNumber && tt.wantApply {
+				t.Errorf("Entry.Revision = %d, want %d", entry.Revision, tt.remote.RevisionNumber)
+			}
+			if localModified := entry.Hash != workspace.Hash(content); localModified != tt.modified {
+				t.Errorf("ローカルの変更 = %v, want %v", localModified, tt.modified)
+			}
+		})
+	}
+}
+
+func TestPuller_MergeConflict(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := testutil.CreateTempDir(t)
+	chdir(t, root)
+	path := filepath.Join(root, "1-手順.md")
+	synced := "---\ntitle: 手順\nnumber: 1\n---\n1. 準備\n"
+	if err := os.WriteFile(path, []byte(synced), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Track(&types.Post{Number: 1, BodyMd: "1. 準備\n", RevisionNumber: 1, UpdatedAt: t0}, path, []byte(synced))
+	if err := os.WriteFile(path, []byte("---\ntitle: 手順\nnumber: 1\n---\n1. ローカルで準備\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &puller{
+		syncer: syncer{client: api.NewClient("test-team", "token", mock.NewMockHTTPClient()), state: state},
+		local:  map[int]string{1: path},
+	}
+	remote := &types.Post{Number: 1, Name: "手順", BodyMd: "1. リモートで準備\n", RevisionNumber: 2, UpdatedAt: t0.Add(time.Hour)}
+	p.apply(remote)
+	if p.conflicts != 1 {
+		t.Fatalf("conflicts = %d, want 1", p.conflicts)
+	}
+
+	compare := func() workspace.Change {
+		t.Helper()
+		local, err := workspace.ScanFiles(root, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		items := state.Compare(local, []*types.Post{remote})
+		if len(items) != 1 {
+			t.Fatalf("Compare() = %v", items)
+		}
+		return items[0].Change
+	}
+
+	// 競合マーカーが残っている間はローカルの変更としてアップロードしない
+	if got := compare(); got != workspace.Conflict {
+		t.Errorf("競合を書き込んだ後の Change = %v, want %v", got, workspace.Conflict)
+	}
+
+	// 解消すればローカルの変更として反映できる
+	if err := os.WriteFile(path, []byte("---\ntitle: 手順\nnumber: 1\n---\n1. 両方で準備\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := compare(); got != workspace.LocalModified {
+		t.Errorf("解消した後の Change = %v, want %v", got, workspace.LocalModified)
+	}
+}
+
+func TestPuller_MergeFrontMatter(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := testutil.CreateTempDir(t)
+	chdir(t, root)
+	path := filepath.Join(root, "1-手順.md")
+	synced := "---\ntitle: 手順\ncategory: 開発\ntags:\n    - a\nwip: true\nnumber: 1\n---\n1. 準備\n2. 実行\n3. 確認\n"
+	if err := os.WriteFile(path, []byte(synced), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Track(&types.Post{Number: 1, Name: "手順", Category: "開発", Tags: []string{"a"}, Wip: true, BodyMd: "1. 準備\n2. 実行\n3. 確認\n", RevisionNumber: 1, UpdatedAt: t0}, path, []byte(synced))
+	// ローカルではタイトルと本文の1行目を変更する
+	if err := os.WriteFile(path, []byte("---\ntitle: 手順書\ncategory: 開発\ntags:\n    - a\nwip: true\nnumber: 1\n---\n1. 準備する\n2. 実行\n3. 確認\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &puller{
+		syncer: syncer{client: api.NewClient("test-team", "token", mock.NewMockHTTPClient()), state: state},
+		local:  map[int]string{1: path},
+	}
+	// リモートではタグ・WIP と本文の3行目を変更する
+	remote := &types.Post{Number: 1, Name: "手順", Category: "開発", Tags: []string{"a", "b"}, Wip: false, BodyMd: "1. 準備\n2. 実行\n3. 確認する\n", RevisionNumber: 2, UpdatedAt: t0.Add(time.Hour)}
+	if !p.apply(remote) || p.merged != 1 {
+		t.Fatalf("apply() merged = %d, want 1", p.merged)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Title != "手順書" || fm.Category != "開発" || strings.Join(fm.Tags, ",") != "a,b" || fm.Wip {
+		t.Errorf("フロントマター = %+v, want ローカルのタイトルとリモートのタグ・WIP", fm)
+	}
+	if body != "1. 準備する\n2. 実行\n3. 確認する\n" {
+		t.Errorf("本文 = %q", body)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/naming"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/spf13/pflag"
+)
+
+// runPush 最後に同期してから変更したファイルだけをリモートに反映する
+func runPush(cmd *pflag.FlagSet, message string, force, dryRun bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Println("❌ 設定が完了していません")
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+
+	// ファイルの指定が無ければワークスペース内の記事ファイルすべてが対象
+	files := cmd.Args()
+	if len(files) == 0 {
+		if files, err = workspacePostFiles(cfg.Project()); err != nil {
+			fmt.Printf("❌ ファイルの読み込みに失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	state, err := workspace.LoadWorkspaceState()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if dryRun {
+		fmt.Println("（--dry-run: 変更は行いません）")
+		fmt.Println()
+	}
+	p := &pusher{
+		syncer: syncer{
+			client:   client,
+			state:    state,
+			defaults: cfg.GetDefaults(),
+			message:  message,
+			dryRun:   dryRun,
+		},
+		force: force,
+	}
+	for _, file := range files {
+		p.apply(file)
+	}
+
+	// sync・pull を使っていないワークスペースでは、3-wayマージのベースだけを保存する
+	if !dryRun {
+		if err := state.SaveTracked(); err != nil {
+			fmt.Printf("⚠️  同期状態の記録に失敗しました: %v\n", err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ 反映完了: 更新 %d件 / 変更なし %d件\n", p.pushed, p.unchanged)
+	if p.conflicts > 0 {
+		fmt.Printf("⚠️  リモートが更新されていたため %d件をスキップしました\n", p.conflicts)
+		fmt.Println("💡 'esa-cli pull' でマージしてから再度 push するか、--force で上書きしてください")
+	}
+	if p.conflicts > 0 || p.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// pusher ローカルで変更したファイルだけをリモートに反映する
+type pusher struct {
+	syncer
+	// force リモートが更新されていても上書きする
+	force bool
+
+	unchanged int
+}
+
+// apply ファイルが変更されていればリモートに反映する
+// 最後に同期したときの内容のハッシュが同じならAPIを呼ばずにスキップし、
+// それ以外はリモートの記事と比較して、違いがあるときだけ更新する
+func (p *pusher) apply(path string) {
+	rel := p.state.Rel(path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		p.failed++
+		return
+	}
+	fm, body, err := p.parse(content)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		p.failed++
+		return
+	}
+	if fm.Title == "" {
+		fmt.Printf("❌ %s: Front Matterにタイトルがありません\n", rel)
+		p.failed++
+		return
+	}
+	// 競合を解消していないファイルはリモートを確認する前に止める（watch の自動反映も同じ）
+	if err := diff.CheckConflictMarkers(body); err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		fmt.Println("   💡 <<<<<<< 〜 >>>>>>> の部分を解消してから反映してください")
+		p.failed++
+		return
+	}
+	number := fm.Number
+	if number == 0 {
+		n, ok := naming.NumberFromFileName(path)
+		if !ok {
+			fmt.Printf("❌ %s: 記事番号が分かりません\n", rel)
+			p.failed++
+			return
+		}
+		number = n
+	}
+
+	entry, tracked := p.state.Entry(number)
+	if tracked && entry.Hash == workspace.Hash(content) {
+		p.unchanged++
+		return
+	}
+
+	post, err := p.client.FetchPost(context.Background(), number)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Printf("❌ %s: 記事 #%d がリモートにありません（削除された可能性があります）\n", rel, number)
+		p.failed++
+		return
+	}
+	if err != nil {
+		fmt.Printf("❌ %s: 記事の取得に失敗しました: %v\n", rel, err)
+		p.failed++
+		return
+	}
+
+	upload := convert.ToUpload(p.linker(), number, body, filepath.Dir(path)).Text
+	summary := workspace.Summary(post, fm, upload)
+	if summary == "" {
+		// 内容が同じなら同期済みとして記録する
+		if !p.dryRun {
+			p.state.Track(post, path, content)
+		}
+		p.unchanged++
+		return
+	}
+	if workspace.RemoteMoved(entry, fm, post) && !p.force {
+		fmt.Printf("⚠️  スキップ: %s（リモートが更新されています / 変更: %s）\n", rel, summary)
+		p.conflicts++
+		return
+	}
+
+	fmt.Printf("⬆️  更新: %s（%s）\n", rel, summary)
+	p.do(p.push(number, path), &p.pushed)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestPusher_Apply(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	synced := "---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n本文\n"
+	edited := "---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n編集後の本文\n"
+
+	tests := []struct {
+		name          string
+		content       string
+		tracked       bool
+		remote        types.Post
+		force         bool
+		wantRequests  []string
+		wantUnchanged int
+		wantConflicts int
+		wantFailed    int
+	}{
+		{
+			name:          "正常系：同期してから変更の無いファイルはAPIを呼ばない",
+			content:       synced,
+			tracked:       true,
+			remote:        types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "本文", RevisionNumber: 1, UpdatedAt: t0},
+			wantUnchanged: 1,
+		},
+		{
+			name:         "正常系：変更したファイルを更新する",
+			content:      edited,
+			tracked:      true,
+			remote:       types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "本文", RevisionNumber: 1, UpdatedAt: t0},
+			wantRequests: []string{"GET", "PATCH"},
+		},
+		{
+			name:          "正常系：同期状態が無くてもリモートと同じならスキップする",
+			content:       synced,
+			remote:        types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "本文\r\n", RevisionNumber: 1, UpdatedAt: t0},
+			wantRequests:  []string{"GET"},
+			wantUnchanged: 1,
+		},
+		{
+			name:          "異常系：リモートが更新されていたらスキップする",
+			content:       edited,
+			tracked:       true,
+			remote:        types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "リモートの本文", RevisionNumber: 2, UpdatedAt: t1},
+			wantRequests:  []string{"GET"},
+			wantConflicts: 1,
+		},
+		{
+			name:         "正常系：--force ならリモートが更新されていても上書きする",
+			content:      edited,
+			tracked:      true,
+			remote:       types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "リモートの本文", RevisionNumber: 2, UpdatedAt: t1},
+			force:        true,
+			wantRequests: []string{"GET", "PATCH"},
+		},
+		{
+			name:       "異常系：競合マーカーが残っているファイルはアップロードしない",
+			content:    "---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n<<<<<<< ローカル\n編集後の本文\n=======\nリモートの本文\n>>>>>>> リモート\n",
+			tracked:    true,
+			remote:     types.Post{Number: 1, Name: "手順", Category: "開発", BodyMd: "本文", RevisionNumber: 1, UpdatedAt: t0},
+			force:      true,
+			wantFailed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, "1-手順.md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.tracked {
+				state.Track(&types.Post{Number: 1, BodyMd: "本文", RevisionNumber: 1, UpdatedAt: t0}, path, []byte(synced))
+			}
+
+			var requests []string
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req.Method)
+				post := tt.remote
+				if req.Method == http.MethodPatch {
+					var body struct {
+						Post types.UpdatePostBody `json:"post"`
+					}
+					data, _ := io.ReadAll(req.Body)
+					_ = json.Unmarshal(data, &body)
+					post.BodyMd = body.Post.BodyMd
+					post.RevisionNumber++
+				}
+				data, _ := json.Marshal(post)
+				return testutil.CreateMockResponse(t, http.StatusOK, string(data)), nil
+			})
+
+			p := &pusher{
+				syncer: syncer{client: api.NewClient("test-team", "token", mockClient), state: state},
+				force:  tt.force,
+			}
+			p.apply(path)
+
+			if len(requests) != len(tt.wantRequests) {
+				t.Fatalf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+			for i := range requests {
+				if requests[i] != tt.wantRequests[i] {
+					t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+				}
+			}
+			if p.unchanged != tt.wantUnchanged || p.conflicts != tt.wantConflicts || p.failed != tt.wantFailed {
+				t.Errorf("unchanged = %d, conflicts = %d, failed = %d, want %d, %d, %d", p.unchanged, p.conflicts, p.failed, tt.wantUnchanged, tt.wantConflicts, tt.wantFailed)
+			}
+		})
+	}
+}
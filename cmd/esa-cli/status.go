@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// statusLabels 差分の種類ごとの見出しと記号（表示順）
+var statusLabels = []struct {
+	change workspace.Change
+	mark   string
+	label  string
+}{
+	{workspace.LocalModified, "M", "ローカルで変更"},
+	{workspace.RemoteModified, "R", "リモートで変更"},
+	{workspace.Conflict, "C", "両方で変更（競合）"},
+	{workspace.NewLocal, "A", "新しい下書き"},
+	{workspace.NewRemote, "N", "リモートの新しい記事"},
+	{workspace.RemoteDeleted, "D", "リモートで削除"},
+	{workspace.LocalDeleted, "X", "ローカルで削除"},
+	{workspace.Untracked, "?", "未追跡"},
+}
+
+// statusEntry status --json の出力
+type statusEntry struct {
+	Change workspace.Change `json:"change"`
+	Number int              `json:"number,omitempty"`
+	Path   string           `json:"path,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	// RemoteRevision リモートのリビジョン番号（リモートを確認しなかった場合は省略）
+	RemoteRevision int `json:"remote_revision,omitempty"`
+	// SyncedRevision 最後に同期したときのリビジョン番号
+	SyncedRevision int `json:"synced_revision,omitempty"`
+}
+
+// runStatus ワークスペースのローカルとリモートの変更を表示する
+func runStatus(cmd *pflag.FlagSet, category string, localOnly, jsonOutput bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Fprintln(os.Stderr, "💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	project := cfg.Project()
+	category = project.CategoryFor(category)
+	root, err := workspace.Root()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	local, err := workspace.ScanFiles(root, project.Ignored)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ ファイルの読み込みに失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
+	var items []workspace.Item
+	if localOnly {
+		items = localChanges(state, local)
+	} else {
+		if cfg.AccessToken == "" || cfg.TeamName == "" {
+			fmt.Fprintln(os.Stderr, "❌ 設定が完了していません")
+			fmt.Fprintln(os.Stderr, "💡 'esa-cli setup' で初期設定を行うか、--local でローカルの変更だけを確認してください")
+			os.Exit(1)
+		}
+		client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+		remote, err := remotePosts(client, category, state)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 記事の取得に失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+		items = state.CompareIn(category, local, remote)
+	}
+
+	entries := statusEntries(state, items)
+	if jsonOutput {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	printStatus(root, category, localOnly, entries)
+}
+
+// remotePosts 比較するリモートの記事を取得する
+// カテゴリ（指定が無ければ同期済みの記事のカテゴリ）の一覧でまとめて取得し、
+// 一覧に無かった同期済みの記事だけを1件ずつ取得して、削除されたのかカテゴリを移動したのかを確かめる
+func remotePosts(client *api.Client, category string, state *workspace.State) ([]*types.Post, error) {
+	categories := []string{category}
+	if category == "" {
+		categories = trackedCategories(state)
+	}
+
+	var posts []*types.Post
+	found := map[int]bool{}
+	for _, c := range categories {
+		listed, err := listCategoryPosts(client, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range listed {
+			if !found[post.Number] {
+				found[post.Number] = true
+				posts = append(posts, post)
+			}
+		}
+	}
+
+	for _, e := range state.Entries() {
+		if found[e.Number] || !e.InCategory(category) {
+			continue
+		}
+		post, err := client.FetchPost(context.Background(), e.Number)
+		if errors.Is(err, api.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+// trackedCategories 同期済みの記事のカテゴリ（他のカテゴリの配下にあるものと、カテゴリの無いものは除く）
+func trackedCategories(state *workspace.State) []string {
+	seen := map[string]bool{}
+	var categories []string
+	for _, e := range state.Entries() {
+		if e.Category != "" && !seen[e.Category] {
+			seen[e.Category] = true
+			categories = append(categories, e.Category)
+		}
+	}
+	sort.Strings(categories)
+
+	var roots []string
+	for _, c := range categories {
+		nested := false
+		for _, root := range roots {
+			if strings.HasPrefix(c, root+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			roots = append(roots, c)
+		}
+	}
+	return roots
+}
+
+// localChanges リモートを確認せずに、ローカルの変更だけを判定する
+func localChanges(state *workspace.State, local []workspace.LocalFile) []workspace.Item {
+	// 同期済みの記事はリモートが変わっていないものとして比較する
+	var remote []*types.Post
+	for _, e := range state.Entries() {
+		remote = append(remote, &types.Post{Number: e.Number, RevisionNumber: e.Revision, UpdatedAt: e.UpdatedAt})
+	}
+	return state.Compare(local, remote)
+}
+
+// statusEntries 表示する差分（変更の無い記事とタイトルの無いファイルは除く）
+func statusEntries(state *workspace.State, items []workspace.Item) []statusEntry {
+	entries := []statusEntry{}
+	for _, item := range items {
+		if item.Change == workspace.Unchanged {
+			continue
+		}
+		entry := statusEntry{Change: item.Change, Number: item.Number}
+		if item.Path != "" {
+			entry.Path = state.Rel(item.Path)
+		} else if item.Entry != nil {
+			entry.Path = item.Entry.Path
+		}
+		if item.Entry != nil {
+			entry.SyncedRevision = item.Entry.Revision
+		}
+		if item.Post != nil {
+			entry.Title = item.Post.FullName
+			entry.RemoteRevision = item.Post.RevisionNumber
+		}
+		if item.Path != "" {
+			title, ok := draftTitle(item.Path)
+			if item.Change == workspace.NewLocal && !ok {
+				continue
+			}
+			if entry.Title == "" {
+				entry.Title = title
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// draftTitle ファイルのFront Matterのタイトル（タイトルが無ければ記事として扱わない）
+func draftTitle(path string) (string, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	fm, _, err := markdown.ParseContent(content)
+	if err != nil || fm.Title == "" {
+		return "", false
+	}
+	return fm.Title, true
+}
+
+func printStatus(root, category string, localOnly bool, entries []statusEntry) {
+	fmt.Printf("📂 ワークスペース: %s\n", root)
+	if category != "" {
+		fmt.Printf("📁 カテゴリ: %s\n", category)
+	}
+	if localOnly {
+		fmt.Println("   （--local: リモートの変更は確認していません）")
+	}
+	fmt.Println()
+
+	if len(entries) == 0 {
+		fmt.Println("✅ 変更はありません")
+		return
+	}
+	for _, l := range statusLabels {
+		var group []statusEntry
+		for _, e := range entries {
+			if e.Change == l.change {
+				group = append(group, e)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d件):\n", l.label, len(group))
+		for _, e := range group {
+			name := e.Path
+			if name == "" {
+				name = e.Title
+			}
+			if e.Number > 0 {
+				fmt.Printf("  %s  [%d] %s\n", l.mark, e.Number, name)
+			} else {
+				fmt.Printf("  %s  %s\n", l.mark, name)
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println("💡 'esa-cli sync' で同期できます")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestStatusEntries_LocalOnly(t *testing.T) {
+	root := testutil.CreateTempDir(t)
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	unchanged := write("1-変更なし.md", "---\ntitle: 変更なし\nnumber: 1\n---\n\n本文\n")
+	modified := write("2-変更あり.md", "---\ntitle: 変更あり\nnumber: 2\n---\n\n編集後\n")
+	write("下書き.md", "---\ntitle: 下書き\n---\n\n本文\n")
+	write("README.md", "# README\n")
+	write("5-未追跡.md", "---\ntitle: 未追跡\n---\n\n本文\n")
+
+	state, _ := workspace.LoadState(root)
+	content, _ := os.ReadFile(unchanged)
+	state.Track(&types.Post{Number: 1, RevisionNumber: 1, UpdatedAt: time.Now()}, unchanged, content)
+	state.Track(&types.Post{Number: 2, RevisionNumber: 1, UpdatedAt: time.Now()}, modified, []byte("編集前"))
+	state.Track(&types.Post{Number: 3, RevisionNumber: 1, UpdatedAt: time.Now()}, filepath.Join(root, "3-削除.md"), []byte("本文"))
+
+	local, err := workspace.ScanFiles(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := statusEntries(state, localChanges(state, local))
+
+	want := map[string]workspace.Change{
+		"2-変更あり.md": workspace.LocalModified,
+		"3-削除.md":   workspace.LocalDeleted,
+		"下書き.md":    workspace.NewLocal,
+		"5-未追跡.md":  workspace.Untracked,
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("statusEntries() = %+v, want %d entries", entries, len(want))
+	}
+	for _, e := range entries {
+		if want[e.Path] != e.Change {
+			t.Errorf("statusEntries()[%s] = %q, want %q", e.Path, e.Change, want[e.Path])
+		}
+	}
+}
+
+func TestRemotePosts(t *testing.T) {
+	tests := []struct {
+		name         string
+		category     string
+		wantRequests []string
+		wantNumbers  []int
+	}{
+		{
+			name:     "正常系：同期済みの記事のカテゴリごとに一覧で取得し、一覧に無い記事だけを取得する",
+			category: "",
+			wantRequests: []string{
+				"GET /v1/teams/test-team/posts in:設計",
+				"GET /v1/teams/test-team/posts in:開発",
+				"GET /v1/teams/test-team/posts/3",
+				"GET /v1/teams/test-team/posts/4",
+			},
+			wantNumbers: []int{2, 1, 4},
+		},
+		{
+			name:     "正常系：カテゴリを指定すればカテゴリ外で同期した記事は取得しない（カテゴリの記録が無い記事は取得する）",
+			category: "開発",
+			wantRequests: []string{
+				"GET /v1/teams/test-team/posts in:開発",
+				"GET /v1/teams/test-team/posts/3",
+				"GET /v1/teams/test-team/posts/4",
+			},
+			wantNumbers: []int{1, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			state, _ := workspace.LoadState(root)
+			// 1: 開発 2: 設計 3: 開発/メモ（削除済み） 4: カテゴリの記録なし（別カテゴリに移動済み）
+			state.Track(&types.Post{Number: 1, Category: "開発"}, filepath.Join(root, "1.md"), nil)
+			state.Track(&types.Post{Number: 2, Category: "設計"}, filepath.Join(root, "2.md"), nil)
+			state.Track(&types.Post{Number: 3, Category: "開発/メモ"}, filepath.Join(root, "3.md"), nil)
+			state.Track(&types.Post{Number: 4}, filepath.Join(root, "4.md"), nil)
+
+			var requests []string
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+				request := req.Method + " " + req.URL.Path
+				if q := req.URL.Query().Get("q"); q != "" {
+					request += " " + q
+				}
+				requests = append(requests, request)
+				switch {
+				case strings.HasSuffix(req.URL.Path, "/posts/4"):
+					return testutil.CreateMockResponse(t, http.StatusOK, `{"number": 4, "category": "アーカイブ"}`), nil
+				case strings.Contains(req.URL.Query().Get("q"), "in:開発"):
+					return testutil.CreateMockResponse(t, http.StatusOK, `{"posts": [{"number": 1, "category": "開発"}]}`), nil
+				case strings.Contains(req.URL.Query().Get("q"), "in:設計"):
+					return testutil.CreateMockResponse(t, http.StatusOK, `{"posts": [{"number": 2, "category": "設計"}]}`), nil
+				}
+				return testutil.CreateMockResponse(t, http.StatusNotFound, `{}`), nil
+			})
+
+			posts, err := remotePosts(api.NewClient("test-team", "token", mockClient), tt.category, state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+			var numbers []int
+			for _, post := range posts {
+				numbers = append(numbers, post.Number)
+			}
+			if !reflect.DeepEqual(numbers, tt.wantNumbers) {
+				t.Errorf("remotePosts() = %v, want %v", numbers, tt.wantNumbers)
+			}
+		})
+	}
+}
+
+func TestTrackedCategories(t *testing.T) {
+	state, _ := workspace.LoadState(testutil.CreateTempDir(t))
+	for i, category := range []string{"開発/メモ", "開発", "開発-設計", "設計/API", "", "開発-設計/詳細"} {
+		state.Track(&types.Post{Number: i + 1, Category: category}, "x.md", nil)
+	}
+	want := []string{"設計/API", "開発", "開発-設計"}
+	if got := trackedCategories(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("trackedCategories() = %v, want %v", got, want)
+	}
+}
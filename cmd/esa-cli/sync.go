@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// runSync ワークスペースとカテゴリの記事を双方向に同期する
+func runSync(cmd *pflag.FlagSet, category string, dryRun bool, message string) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Println("❌ 設定が完了していません")
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+
+	// 同期するカテゴリはフラグ、無ければプロジェクト設定のルートカテゴリ
+	project := cfg.Project()
+	category = project.CategoryFor(category)
+	if category == "" {
+		fmt.Println("❌ 同期するカテゴリを指定してください")
+		fmt.Println("💡 -c オプションか、.esa-cli.yml の category で指定できます")
+		os.Exit(1)
+	}
+
+	root, err := workspace.Root()
+	if err != nil {
+		fmt.Printf("❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("🔄 ワークスペースを同期します...")
+	fmt.Printf("   カテゴリ: %s\n", category)
+	fmt.Printf("   ディレクトリ: %s\n", root)
+	if dryRun {
+		fmt.Println("   （--dry-run: 変更は行いません）")
+	}
+	fmt.Println()
+
+	remote, err := listCategoryPosts(client, category)
+	if err != nil {
+		fmt.Printf("❌ 記事の取得に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+	local, err := workspace.ScanFiles(root, project.Ignored)
+	if err != nil {
+		fmt.Printf("❌ ファイルの読み込みに失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
+	s := &syncer{
+		client:   client,
+		state:    state,
+		defaults: cfg.GetDefaults(),
+		category: category,
+		message:  message,
+		dryRun:   dryRun,
+	}
+	for _, item := range state.CompareIn(category, local, remote) {
+		s.apply(item)
+	}
+
+	if !dryRun {
+		if err := state.Save(); err != nil {
+			fmt.Printf("❌ 同期状態の保存に失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ 同期完了: 取得 %d件 / 更新 %d件 / 作成 %d件\n", s.pulled, s.pushed, s.created)
+	if s.trashed > 0 || s.archived > 0 || s.deleted > 0 {
+		fmt.Printf("🗑️  削除: .trash に移動 %d件 / アーカイブ %d件 / 記事を削除 %d件\n", s.trashed, s.archived, s.deleted)
+	}
+	if s.conflicts > 0 || s.failed > 0 {
+		fmt.Printf("⚠️  競合 %d件 / 失敗 %d件\n", s.conflicts, s.failed)
+		os.Exit(1)
+	}
+}
+
+// listCategoryPosts カテゴリ配下の記事をすべて取得する
+func listCategoryPosts(client *api.Client, category string) ([]*types.Post, error) {
+	return searchCategoryPosts(client, category, "")
+}
+
+// searchCategoryPosts カテゴリ配下の記事のうち、検索条件（例: updated:>2025-01-01）に一致するものをすべて取得する
+func searchCategoryPosts(client *api.Client, category, query string) ([]*types.Post, error) {
+	q := "in:" + category
+	if query != "" {
+		q += " " + query
+	}
+	var posts []*types.Post
+	const perPage = 100
+	for page := 1; ; page++ {
+		pagePosts, err := client.ListPosts(context.Background(), &api.ListPostsOptions{
+			Query: q,
+			Limit: perPage,
+			Page:  page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// in: は前方一致のため、カテゴリ配下の記事だけに絞り込む
+		for _, post := range pagePosts {
+			if post.Category == category || strings.HasPrefix(post.Category, category+"/") {
+				posts = append(posts, post)
+			}
+		}
+		if len(pagePosts) < perPage {
+			return posts, nil
+		}
+	}
+}
+
+// syncer 記事の取得・更新を行い、ワークスペースの同期状態に記録する
+type syncer struct {
+	client   *api.Client
+	state    *workspace.State
+	defaults config.Defaults
+	category string
+	message  string
+	dryRun   bool
+	// keepWip Front Matterに wip が無いファイルはWIPのまま更新する
+	keepWip bool
+	// links 記事リンクの書き換えに使う索引（linker で作成する）
+	links *workspace.Links
+
+	pulled, pushed, created, conflicts, failed int
+	// trashed, archived, deleted 削除された記事・ファイルを扱った件数
+	trashed, archived, deleted int
+}
+
+// apply 差分の種類に応じて同期する
+func (s *syncer) apply(item workspace.Item) {
+	rel := ""
+	if item.Path != "" {
+		rel = s.state.Rel(item.Path)
+	}
+
+	switch item.Change {
+	case workspace.RemoteModified, workspace.NewRemote:
+		path := item.Path
+		if path == "" {
+			path = workspace.PostPath(filepath.Join(s.state.Root(), s.defaults.FileName(item.Post.Number, item.Post.Name, item.Post.Category)), item.Number)
+		} else {
+			path = s.relocate(item.Post, path)
+		}
+		fmt.Printf("⬇️  取得: [%d] %s → %s\n", item.Number, item.Post.FullName, s.state.Rel(path))
+		s.do(s.pull(item.Post, path), &s.pulled)
+	case workspace.LocalModified:
+		fmt.Printf("⬆️  更新: %s\n", rel)
+		s.do(s.push(item.Number, item.Path), &s.pushed)
+	case workspace.NewLocal:
+		s.createFrom(item.Path)
+	case workspace.Untracked:
+		s.adopt(item)
+	case workspace.Conflict:
+		if item.Duplicate {
+			fmt.Printf("⚠️  競合: %s（記事番号 %d のファイルが複数あります）\n", rel, item.Number)
+			s.conflicts++
+			return
+		}
+		fmt.Printf("⚠️  競合: %s（ローカルとリモートの両方が変更されています）\n", rel)
+		s.conflicts++
+	case workspace.RemoteDeleted:
+		s.remoteDeleted(item)
+	case workspace.LocalDeleted:
+		s.localDeleted(item)
+	}
+}
+
+// linker 記事リンクの書き換えに使う索引（実行中は同じものを使う）
+func (s *syncer) linker() *workspace.Links {
+	if s.links == nil {
+		s.links = workspace.NewLinks(s.client.TeamName())
+	}
+	return s.links
+}
+
+func (s *syncer) do(err error, count *int) {
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		s.failed++
+		return
+	}
+	*count++
+}
+
+// pull リモートの記事をローカルファイルに書き込む
+func (s *syncer) pull(post *types.Post, path string) error {
+	if s.dryRun {
+		return nil
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	fm := convert.FrontMatter(post, s.client.TeamName())
+	var content []byte
+	if existing != nil {
+		// ローカルで画像やリンクを書き換えていた場合は同じ形式で書き込む
+		_, oldBody, _ := markdown.ParseContent(existing)
+		upload := convert.ToUpload(s.linker(), post.Number, oldBody, filepath.Dir(path))
+		content, err = markdown.UpdateContent(existing, fm, upload.Local(post.BodyMd, filepath.Dir(path)))
+	} else {
+		content, err = markdown.GenerateContentAs(markdown.Format(s.defaults.FrontMatterFormat), fm, post.BodyMd)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	s.state.Track(post, path, content)
+	s.linker().Track(post.Number, path)
+	return nil
+}
+
+// push ローカルファイルの内容でリモートの記事を更新する
+func (s *syncer) push(number int, path string) error {
+	if s.dryRun {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fm, body, err := s.parse(content)
+	if err != nil {
+		return err
+	}
+	if fm.Title == "" {
+		return fmt.Errorf("Front Matterにタイトルがありません")
+	}
+
+	message := s.message
+	if message == "" {
+		message = s.defaults.CommitMessage(fm.Title, number, filepath.Base(path), time.Now())
+	}
+	upload, err := convert.PrepareUpload(s.linker(), number, body, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	post, err := s.client.UpdatePost(context.Background(), number, types.UpdatePostBody{
+		Name:     fm.Title,
+		Category: fm.Category,
+		Tags:     fm.Tags,
+		BodyMd:   upload.Text,
+		Wip:      fm.Wip,
+		Message:  message,
+	})
+	if err != nil {
+		return err
+	}
+
+	newContent, err := markdown.UpdateContent(content, convert.FrontMatter(post, s.client.TeamName()), upload.Local(post.BodyMd, filepath.Dir(path)))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, newContent, 0644); err != nil {
+		return err
+	}
+	s.state.Track(post, path, newContent)
+	return nil
+}
+
+// parse ローカルファイルのFront Matterと本文を取得する
+// keepWip のときは、Front Matterに wip が書かれていなければWIPとして扱う
+func (s *syncer) parse(content []byte) (types.FrontMatter, string, error) {
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil || !s.keepWip {
+		return fm, body, err
+	}
+	if doc, err := markdown.Parse(content); err == nil {
+		if _, ok := doc.Get("wip"); !ok {
+			fm.Wip = true
+		}
+	}
+	return fm, body, nil
+}
+
+// createFrom 記事番号の無いローカルファイルから記事を作成する
+func (s *syncer) createFrom(path string) {
+	rel := s.state.Rel(path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		s.failed++
+		return
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		s.failed++
+		return
+	}
+	// タイトルの無いファイル（README など）は記事として扱わない
+	if fm.Title == "" {
+		return
+	}
+	if fm.Category == "" {
+		fm.Category = s.category
+	}
+	if fm.Category != s.category && !strings.HasPrefix(fm.Category, s.category+"/") {
+		fmt.Printf("⚠️  スキップ: %s（カテゴリ %s は同期するカテゴリの外です）\n", rel, fm.Category)
+		return
+	}
+
+	fmt.Printf("🆕 作成: %s → %s/%s\n", rel, fm.Category, fm.Title)
+	if s.dryRun {
+		s.created++
+		return
+	}
+
+	message := s.message
+	if message == "" {
+		message = s.defaults.CommitMessage(fm.Title, 0, filepath.Base(path), time.Now())
+	}
+	upload, err := convert.PrepareUpload(s.linker(), 0, body, filepath.Dir(path))
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		s.failed++
+		return
+	}
+	post, err := s.client.CreatePost(context.Background(), types.CreatePostBody{
+		Name:     fm.Title,
+		Category: fm.Category,
+		Tags:     fm.Tags,
+		BodyMd:   upload.Text,
+		Wip:      fm.Wip,
+		Message:  message,
+	})
+	if err != nil {
+		fmt.Printf("   ❌ %v\n", err)
+		s.failed++
+		return
+	}
+
+	newContent, err := markdown.UpdateContent(content, convert.FrontMatter(post, s.client.TeamName()), upload.Local(post.BodyMd, filepath.Dir(path)))
+	if err == nil {
+		err = os.WriteFile(path, newContent, 0644)
+	}
+	if err != nil {
+		fmt.Printf("   ❌ ローカルファイルの更新に失敗しました: %v\n", err)
+		s.failed++
+		return
+	}
+	s.state.Track(post, path, newContent)
+	s.linker().Track(post.Number, path)
+	s.created++
+}
+
+// adopt 同期状態に記録されていないファイルを同期の対象にする
+// sync 以前に fetch したファイルは、Front Matterの remote_updated_at でどちらが変更されたかを判定する
+func (s *syncer) adopt(item workspace.Item) {
+	rel := s.state.Rel(item.Path)
+	if item.Post == nil {
+		fmt.Printf("⚠️  未追跡: %s（記事 #%d が同期するカテゴリに見つかりません）\n", rel, item.Number)
+		return
+	}
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		s.failed++
+		return
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", rel, err)
+		s.failed++
+		return
+	}
+
+	upload := convert.ToUpload(s.linker(), item.Number, body, filepath.Dir(item.Path)).Text
+	fetchedAt, _ := time.Parse(time.RFC3339, fm.RemoteUpdatedAt)
+	switch {
+	case upload == item.Post.BodyMd && fm.Title == item.Post.Name:
+		// 内容が同じならそのまま記録する
+		if !s.dryRun {
+			s.state.Track(item.Post, item.Path, content)
+		}
+	case fetchedAt.Equal(item.Post.UpdatedAt):
+		fmt.Printf("⬆️  更新: %s\n", rel)
+		s.do(s.push(item.Number, item.Path), &s.pushed)
+	default:
+		fmt.Printf("⚠️  競合: %s（同期の記録が無く、ローカルとリモートの内容が異なります）\n", rel)
+		s.conflicts++
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// chdir テスト中だけカレントディレクトリを変更する
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestSyncer_Apply(t *testing.T) {
+	root := testutil.CreateTempDir(t)
+	chdir(t, root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+
+	writeTestFile := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	pushed := writeTestFile("1-ローカル変更.md", "---\ntitle: ローカル変更\ncategory: 開発\nnumber: 1\n---\n\n編集後\n")
+	pulled := writeTestFile("2-リモート変更.md", "---\ntitle: リモート変更\ncategory: 開発\nnumber: 2\nmemo: 残す\n---\n\n古い本文\n")
+	draft := writeTestFile("下書き.md", "---\ntitle: 下書き\n---\n\n新しい記事\n")
+	readme := writeTestFile("README.md", "# README\n")
+
+	state, err := workspace.LoadState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Track(&types.Post{Number: 1, RevisionNumber: 1, UpdatedAt: t0}, pushed, []byte("同期したときの内容"))
+	content, _ := os.ReadFile(pulled)
+	state.Track(&types.Post{Number: 2, RevisionNumber: 1, UpdatedAt: t0}, pulled, content)
+
+	var requests []string
+	mockClient := mock.NewMockHTTPClient()
+	mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		var body struct {
+			Post types.CreatePostBody `json:"post"`
+		}
+		if req.Body != nil {
+			data, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(data, &body)
+		}
+		switch {
+		case req.Method == http.MethodPatch:
+			post := types.Post{Number: 1, Name: body.Post.Name, Category: body.Post.Category, BodyMd: body.Post.BodyMd, RevisionNumber: 2, UpdatedAt: t1}
+			data, _ := json.Marshal(post)
+			return testutil.CreateMockResponse(t, http.StatusOK, string(data)), nil
+		case req.Method == http.MethodPost:
+			post := types.Post{Number: 10, Name: body.Post.Name, Category: body.Post.Category, BodyMd: body.Post.BodyMd, RevisionNumber: 1, UpdatedAt: t1}
+			data, _ := json.Marshal(post)
+			return testutil.CreateMockResponse(t, http.StatusCreated, string(data)), nil
+		}
+		return testutil.CreateMockResponse(t, http.StatusNotFound, `{}`), nil
+	})
+
+	s := &syncer{
+		client:   api.NewClient("test-team", "token", mockClient),
+		state:    state,
+		category: "開発",
+	}
+	remote := []*types.Post{
+		{Number: 1, Name: "ローカル変更", Category: "開発", BodyMd: "編集前\n", RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 2, Name: "リモート変更", Category: "開発", BodyMd: "新しい本文\n", RevisionNumber: 2, UpdatedAt: t1},
+		{Number: 3, Name: "新着", Category: "開発/設計", BodyMd: "新着の本文\n", RevisionNumber: 1, UpdatedAt: t1},
+	}
+	local, err := workspace.ScanFiles(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range state.Compare(local, remote) {
+		s.apply(item)
+	}
+
+	if s.pushed != 1 || s.pulled != 2 || s.created != 1 || s.conflicts != 0 || s.failed != 0 {
+		t.Errorf("syncer = pushed %d, pulled %d, created %d, conflicts %d, failed %d", s.pushed, s.pulled, s.created, s.conflicts, s.failed)
+	}
+	if len(requests) != 2 {
+		t.Errorf("requests = %v, want PATCH and POST", requests)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		contains []string
+	}{
+		{"正常系：ローカルの変更を反映", pushed, []string{"編集後", "remote_updated_at"}},
+		{"正常系：リモートの変更を取得し独自キーを残す", pulled, []string{"新しい本文", "memo: 残す"}},
+		{"正常系：新しいリモートの記事を取得", filepath.Join(root, "3-新着.md"), []string{"新着の本文", "number: 3"}},
+		{"正常系：下書きから記事を作成", draft, []string{"新しい記事", "number: 10", "category: 開発"}},
+		{"エッジケース：タイトルの無いファイルは変更しない", readme, []string{"# README"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%s =\n%s\nwant to contain %q", filepath.Base(tt.path), content, want)
+				}
+			}
+			if number, ok := numberOf(t, tt.path); ok {
+				e, tracked := state.Entry(number)
+				if !tracked || e.Hash != workspace.Hash(content) {
+					t.Errorf("同期状態が記録されていない: %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncer_AdoptUntracked(t *testing.T) {
+	root := testutil.CreateTempDir(t)
+	chdir(t, root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	same := filepath.Join(root, "1-同じ.md")
+	os.WriteFile(same, []byte("---\ntitle: 同じ\nnumber: 1\n---\n\n本文\n"), 0644)
+	changed := filepath.Join(root, "2-両方.md")
+	os.WriteFile(changed, []byte("---\ntitle: 両方\nnumber: 2\nremote_updated_at: \"2025-01-01T00:00:00Z\"\n---\n\nローカル\n"), 0644)
+
+	state, _ := workspace.LoadState(root)
+	s := &syncer{client: api.NewClient("test-team", "token", mock.NewMockHTTPClient()), state: state, category: "開発"}
+	remote := []*types.Post{
+		{Number: 1, Name: "同じ", BodyMd: "本文\n", RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 2, Name: "両方", BodyMd: "リモート\n", RevisionNumber: 3, UpdatedAt: t0.Add(time.Hour)},
+	}
+	local, _ := workspace.ScanFiles(root, nil)
+	for _, item := range state.Compare(local, remote) {
+		s.apply(item)
+	}
+
+	if _, ok := state.Entry(1); !ok {
+		t.Error("内容が同じファイルは同期状態に記録する")
+	}
+	if _, ok := state.Entry(2); ok || s.conflicts != 1 {
+		t.Errorf("リモートも更新されたファイルは競合にする: conflicts = %d", s.conflicts)
+	}
+}
+
+func numberOf(t *testing.T, path string) (int, bool) {
+	t.Helper()
+	files, _ := workspace.ScanFiles(filepath.Dir(path), nil)
+	for _, f := range files {
+		if f.Path == path && f.Number > 0 {
+			return f.Number, true
+		}
+	}
+	return 0, false
+}
+
+func TestSyncer_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		keepWip bool
+		want    bool
+	}{
+		{
+			name:    "正常系：wip の指定が無ければWIPのまま",
+			content: "---\ntitle: 手順\n---\n本文\n",
+			keepWip: true,
+			want:    true,
+		},
+		{
+			name:    "正常系：wip: false なら公開する",
+			content: "---\ntitle: 手順\nwip: false\n---\n本文\n",
+			keepWip: true,
+			want:    false,
+		},
+		{
+			name:    "正常系：keepWip でなければFront Matterのまま",
+			content: "---\ntitle: 手順\n---\n本文\n",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &syncer{keepWip: tt.keepWip}
+			fm, _, err := s.parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			if fm.Wip != tt.want {
+				t.Errorf("Wip = %v, want %v", fm.Wip, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/templates"
+	"github.com/spf13/pflag"
+)
+
+// runTemplates esa.ioのテンプレート記事の一覧を表示する
+func runTemplates(cmd *pflag.FlagSet) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Println("❌ 設定が完了していません")
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	client := newAPIClient(cfg.TeamName, cfg.AccessToken)
+
+	posts, err := templates.List(context.Background(), client)
+	if err != nil {
+		fmt.Printf("❌ テンプレートの取得に失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+	if len(posts) == 0 {
+		fmt.Printf("⚠️  %s カテゴリにテンプレートがありません\n", templates.Category)
+		return
+	}
+
+	fmt.Printf("📋 テンプレート (%d件):\n", len(posts))
+	for _, post := range posts {
+		category, name := templates.Target(post)
+		fmt.Printf("  [%d] %s\n", post.Number, name)
+		if category != "" {
+			fmt.Printf("      カテゴリ: %s\n", category)
+		}
+		if len(post.Tags) > 0 {
+			fmt.Printf("      タグ: %s\n", strings.Join(post.Tags, ", "))
+		}
+	}
+	fmt.Println()
+	fmt.Println("💡 'esa-cli create --from-template <記事番号またはタイトル>' でテンプレートから記事を作成できます")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shellme/esa-cli/internal/convert"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// relocateExisting tree の配置で、ワークスペース内の別の場所にある記事ファイルを保存先に移動する
+// カテゴリが変わった記事や、tree にする前に保存したファイルが残らないようにする
+func relocateExisting(number int, path string) {
+	root, err := workspace.Root()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ワークスペースの読み込みに失敗しました: %v\n", err)
+		return
+	}
+	ix, err := workspace.Scan(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ワークスペースの読み込みに失敗しました: %v\n", err)
+		return
+	}
+	from, err := ix.Relocate(number, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ファイルの移動に失敗しました: %v\n", err)
+		return
+	}
+	if from != "" {
+		fmt.Printf("🚚 移動: %s → %s（カテゴリのディレクトリに合わせました）\n", workspace.RelPath(from), path)
+	}
+}
+
+// movePostFile 記事ファイルを移動する
+// ローカルの画像やファイルへのリンクは、移動先から同じファイルを指すように書き換える
+func movePostFile(links *workspace.Links, number int, from, to string) error {
+	content, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	fm, body, err := markdown.ParseContent(content)
+	if err != nil {
+		return err
+	}
+	root, err := workspace.Root()
+	if err != nil {
+		return err
+	}
+	upload := convert.ToUpload(links, number, body, filepath.Dir(from))
+	if err := workspace.MoveFile(from, to, root); err != nil {
+		return err
+	}
+	links.Track(number, to)
+	if upload.Text == body {
+		return nil
+	}
+	newContent, err := markdown.UpdateContent(content, fm, upload.Local(upload.Text, filepath.Dir(to)))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, newContent, 0644)
+}
+
+// moveToCategory tree の配置で、カテゴリを変更した記事ファイルを新しいカテゴリのディレクトリに移動する
+// 元のカテゴリのディレクトリに無いファイルは移動しない。移動後のパスを返す
+func moveToCategory(links *workspace.Links, number int, path, oldCategory, newCategory string) string {
+	to, ok := workspace.CategoryPath(path, oldCategory, newCategory)
+	if !ok {
+		return path
+	}
+	if err := movePostFile(links, number, path, to); err != nil {
+		fmt.Printf("⚠️  ファイルの移動に失敗しました: %v\n", err)
+		return path
+	}
+	fmt.Printf("🚚 移動: %s → %s\n", path, to)
+	return to
+}
+
+// relocate tree の配置で、リモートでカテゴリが変わった記事ファイルを新しいカテゴリのディレクトリに移動する
+// ファイル名はそのまま残す。移動後のパス（移動しなければ path）を返す
+func (s *syncer) relocate(post *types.Post, path string) string {
+	if !s.defaults.TreeLayout() {
+		return path
+	}
+	expected := filepath.Join(s.state.Root(), s.defaults.FileName(post.Number, post.Name, post.Category))
+	to := filepath.Join(filepath.Dir(expected), filepath.Base(path))
+	if abs, err := filepath.Abs(path); err != nil || abs == to {
+		return path
+	}
+
+	fmt.Printf("🚚 移動: %s → %s（カテゴリ: %s）\n", s.state.Rel(path), s.state.Rel(to), post.Category)
+	if s.dryRun {
+		return path
+	}
+	if err := movePostFile(s.linker(), post.Number, path, to); err != nil {
+		fmt.Printf("   ⚠️  ファイルの移動に失敗しました: %v\n", err)
+		return path
+	}
+	s.state.Move(post.Number, to)
+	return to
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestSyncer_Relocate(t *testing.T) {
+	tree := true
+	tests := []struct {
+		name     string
+		defaults config.Defaults
+		file     string
+		category string
+		want     string
+	}{
+		{
+			name:     "正常系：リモートでカテゴリが変わったら新しいカテゴリのディレクトリに移動する",
+			defaults: config.Defaults{Tree: &tree},
+			file:     "開発/1-手順.md",
+			category: "設計/インフラ",
+			want:     "設計/インフラ/1-手順.md",
+		},
+		{
+			name:     "正常系：ファイル名は変えない",
+			defaults: config.Defaults{Tree: &tree},
+			file:     "開発/手順メモ.md",
+			category: "設計",
+			want:     "設計/手順メモ.md",
+		},
+		{
+			name:     "正常系：カテゴリが同じなら移動しない",
+			defaults: config.Defaults{Tree: &tree},
+			file:     "開発/1-手順.md",
+			category: "開発",
+			want:     "開発/1-手順.md",
+		},
+		{
+			name:     "正常系：tree でなければ移動しない",
+			file:     "開発/1-手順.md",
+			category: "設計",
+			want:     "開発/1-手順.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, filepath.FromSlash(tt.file))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			content := []byte("---\ntitle: 手順\ncategory: 開発\nnumber: 1\n---\n本文\n")
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			post := &types.Post{Number: 1, Name: "手順", Category: tt.category, BodyMd: "本文", RevisionNumber: 1}
+			state.Track(post, path, content)
+
+			s := &syncer{
+				client:   api.NewClient("test-team", "token", mock.NewMockHTTPClient()),
+				state:    state,
+				defaults: tt.defaults,
+			}
+			got := s.relocate(post, path)
+
+			if rel := state.Rel(got); rel != tt.want {
+				t.Errorf("relocate() = %v, want %v", rel, tt.want)
+			}
+			if _, err := os.Stat(got); err != nil {
+				t.Errorf("移動先のファイルがありません: %v", err)
+			}
+			if entry, _ := state.Entry(1); entry.Path != tt.want {
+				t.Errorf("Entry.Path = %v, want %v", entry.Path, tt.want)
+			}
+		})
+	}
+}
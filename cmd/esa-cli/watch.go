@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/spf13/pflag"
+)
+
+// runWatch ワークスペースのMarkdownファイルを監視し、保存されたファイルをリモートに反映する
+// 反映は push と同じく、リモートが更新されていればスキップする
+func runWatch(cmd *pflag.FlagSet, interval, debounce time.Duration, message string) {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ 設定の読み込みに失敗しました: %v\n", err)
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+
+	if cfg.AccessToken == "" || cfg.TeamName == "" {
+		fmt.Println("❌ 設定が完了していません")
+		fmt.Println("💡 'esa-cli setup' で初期設定を行ってください")
+		os.Exit(1)
+	}
+	if interval <= 0 {
+		fmt.Println("❌ --interval には正の値を指定してください")
+		os.Exit(1)
+	}
+
+	// ディレクトリの指定が無ければワークスペース全体が対象
+	dir := ""
+	if len(cmd.Args()) > 0 {
+		dir = cmd.Args()[0]
+	} else if dir, err = workspace.Root(); err != nil {
+		fmt.Printf("❌ エラー: %v\n", err)
+		os.Exit(1)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		fmt.Printf("❌ ディレクトリが見つかりません: %s\n", dir)
+		os.Exit(1)
+	}
+
+	state, err := workspace.LoadWorkspaceState()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	watcher, err := workspace.NewWatcher(dir, cfg.Project().Ignored, debounce)
+	if err != nil {
+		fmt.Printf("❌ ファイルの読み込みに失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := &pusher{
+		syncer: syncer{
+			client:   newAPIClient(cfg.TeamName, cfg.AccessToken),
+			state:    state,
+			defaults: cfg.GetDefaults(),
+			message:  message,
+			keepWip:  true,
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	abs, _ := filepath.Abs(dir)
+	fmt.Printf("👀 監視中: %s\n", state.Rel(abs))
+	fmt.Printf("💡 保存してから %s 変更が無ければ反映します（Ctrl+Cで終了）\n", debounce)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			fmt.Printf("👋 監視を終了しました: 更新 %d件 / スキップ %d件 / 失敗 %d件\n", p.pushed, p.conflicts, p.failed)
+			return
+		case now := <-ticker.C:
+			files, err := watcher.Poll(now)
+			if err != nil {
+				fmt.Printf("[%s] ⚠️  ファイルの読み込みに失敗しました: %v\n", now.Format("15:04:05"), err)
+				continue
+			}
+			for _, path := range files {
+				p.watch(path, now)
+				// 更新後に書き戻した Front Matter は変更として扱わない
+				watcher.Touch(path)
+			}
+		}
+	}
+}
+
+// watch 変更されたファイルを反映し、結果を記録する
+func (p *pusher) watch(path string, now time.Time) {
+	fmt.Printf("[%s] 📝 変更を検出: %s\n", now.Format("15:04:05"), p.state.Rel(path))
+	unchanged, conflicts := p.unchanged, p.conflicts
+	p.apply(path)
+	switch {
+	case p.unchanged > unchanged:
+		fmt.Println("   リモートとの違いはありません")
+	case p.conflicts > conflicts:
+		fmt.Println("   💡 'esa-cli pull' でマージしてから保存し直してください")
+	}
+
+	// sync・pull を使っていないワークスペースでは、3-wayマージのベースだけを保存する
+	if err := p.state.SaveTracked(); err != nil {
+		fmt.Printf("   ⚠️  同期状態の記録に失敗しました: %v\n", err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/api/mock"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestPusher_Watch(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	synced := "---\ntitle: 手順\nnumber: 1\n---\n本文\n"
+
+	tests := []struct {
+		name         string
+		content      string
+		wantRequests int
+		wantFailed   int
+	}{
+		{
+			name:         "正常系：保存したファイルを反映する",
+			content:      "---\ntitle: 手順\nnumber: 1\n---\n編集後の本文\n",
+			wantRequests: 2,
+		},
+		{
+			name:       "異常系：pull で競合したまま保存したファイルはアップロードしない",
+			content:    "---\ntitle: 手順\nnumber: 1\n---\n<<<<<<< ローカル\n編集後の本文\n=======\nリモートの本文\n>>>>>>> リモート\n",
+			wantFailed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := testutil.CreateTempDir(t)
+			chdir(t, root)
+			path := filepath.Join(root, "1-手順.md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := workspace.LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state.Track(&types.Post{Number: 1, BodyMd: "本文", RevisionNumber: 1, UpdatedAt: t0}, path, []byte(synced))
+
+			requests := 0
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetHandler(func(req *http.Request) (*http.Response, error) {
+				requests++
+				return testutil.CreateMockResponse(t, http.StatusOK, `{"number": 1, "name": "手順", "body_md": "本文", "revision_number": 1, "updated_at": "2025-01-01T00:00:00Z"}`), nil
+			})
+			p := &pusher{syncer: syncer{client: api.NewClient("test-team", "token", mockClient), state: state, keepWip: true}}
+			p.watch(path, t0)
+
+			if requests != tt.wantRequests || p.failed != tt.wantFailed {
+				t.Errorf("requests = %d, failed = %d, want %d, %d", requests, p.failed, tt.wantRequests, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/assets"
 	"github.com/shellme/esa-cli/internal/config"
 	"github.com/shellme/esa-cli/internal/mac"
 	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
 	"github.com/shellme/esa-cli/pkg/types"
 	"github.com/spf13/pflag"
 )
@@ -24,7 +27,13 @@ func main() {
 		user     = pflag.StringP("user", "u", "", "作成者でフィルタ")
 		query    = pflag.StringP("query", "q", "", "検索ワードでフィルタ")
 		limit    = pflag.IntP("limit", "l", 10, "取得件数制限")
+
+		localLinks = pflag.Bool("local-links", false, "ワークスペース内にある記事へのリンクをローカルファイルへのリンクに書き換える")
+		withAssets = pflag.Bool("with-assets", false, "記事中の画像を assets/ にダウンロードしてローカルのパスに書き換える")
+		tree       = pflag.Bool("tree", false, "カテゴリをディレクトリにして保存する（開発/API/123-タイトル.md）")
 	)
+	pflag.StringVar(&config.SelectedProfile, "profile", "", "使用するプロファイル（環境変数 ESA_PROFILE でも指定可）")
+	pflag.StringVar(&config.SelectedProfile, "team", "", "使用するプロファイル（--profileの別名）")
 	pflag.Parse()
 
 	// 設定の読み込み
@@ -43,6 +52,18 @@ func main() {
 	// APIクライアントの作成
 	client := api.NewClient(cfg.TeamName, cfg.AccessToken, http.DefaultClient)
 
+	// 件数の指定が無ければ設定のデフォルトを使う
+	defaults := cfg.GetDefaults()
+	if !pflag.CommandLine.Changed("limit") && defaults.ListLimit > 0 {
+		*limit = defaults.ListLimit
+	}
+	if pflag.CommandLine.Changed("tree") {
+		defaults.Tree = tree
+	}
+
+	// プロジェクト設定のルートカテゴリ配下に絞り込む
+	*category = cfg.Project().CategoryFor(*category)
+
 	// 検索条件の表示
 	fmt.Println("🔍 記事を検索中...")
 	if *category != "" {
@@ -131,8 +152,32 @@ func main() {
 	fmt.Printf("📥 記事のダウンロードを開始します...\n")
 	fmt.Printf("   対象記事数: %d件\n\n", len(posts))
 
+	// 画像の保存先
+	var store *assets.Store
+	if *withAssets {
+		if store, err = assets.OpenWorkspace(http.DefaultClient); err != nil {
+			fmt.Fprintf(os.Stderr, "%s の読み込みに失敗しました: %v\n", assets.ManifestFile, err)
+			os.Exit(1)
+		}
+	}
+
+	// tree の配置では、ワークスペース内の別の場所にある記事ファイルを保存先に移動する
+	var index *workspace.Index
+	if defaults.TreeLayout() {
+		root, err := workspace.Root()
+		if err == nil {
+			index, err = workspace.Scan(root)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ワークスペースの読み込みに失敗しました: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// 記事のダウンロード
 	successCount := 0
+	var saved []string
+	fetched := map[string]*types.Post{}
 	for _, post := range posts {
 		fmt.Printf("📥 ダウンロード中: [%d] %s\n", post.Number, post.Name)
 
@@ -149,18 +194,55 @@ func main() {
 			Category:        detail.Category,
 			Tags:            detail.Tags,
 			Wip:             detail.Wip,
+			Number:          detail.Number,
+			Team:            client.TeamName(),
 			RemoteUpdatedAt: detail.UpdatedAt.Format(time.RFC3339),
 		}
 
+		// ファイル名の生成（出力先・ファイル名テンプレートは設定に従う）
+		// 別の記事のファイルがあれば上書きしないよう番号を付ける
+		filename := workspace.PostPath(filepath.Join(defaults.OutputDir, defaults.FileName(post.Number, post.Name, detail.Category)), post.Number)
+		if dir := filepath.Dir(filename); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Printf("   ❌ ディレクトリの作成に失敗しました: %v\n", err)
+				continue
+			}
+		}
+		if index != nil {
+			from, err := index.Relocate(detail.Number, filename)
+			if err != nil {
+				fmt.Printf("   ❌ ファイルの移動に失敗しました: %v\n", err)
+				continue
+			}
+			if from != "" {
+				fmt.Printf("   🚚 移動: %s → %s（カテゴリのディレクトリに合わせました）\n", workspace.RelPath(from), filename)
+			}
+		}
+
+		// 記事中の画像をダウンロードしてローカルのパスに書き換える
+		body := detail.BodyMd
+		if store != nil {
+			var errs []error
+			body, errs = store.Localize(context.Background(), detail.Number, body, filepath.Dir(filename))
+			for _, err := range errs {
+				fmt.Printf("   ⚠️  画像のダウンロードに失敗しました: %v\n", err)
+			}
+		}
+
 		// Markdownコンテンツの生成
-		content, err := markdown.GenerateContent(fm, detail.BodyMd)
+		content, err := markdown.GenerateContentAs(markdown.Format(defaults.FrontMatterFormat), fm, body)
 		if err != nil {
 			fmt.Printf("   ❌ ファイル内容の生成に失敗しました: %v\n", err)
 			continue
 		}
 
-		// ファイル名の生成
-		filename := fmt.Sprintf("%d-%s.md", post.Number, post.Name)
+		// 既存のファイルがあれば独自のFront Matterを残したまま書き換える
+		if existing, err := os.ReadFile(filename); err == nil {
+			if content, err = markdown.UpdateContent(existing, fm, body); err != nil {
+				fmt.Printf("   ❌ ファイル内容の生成に失敗しました: %v\n", err)
+				continue
+			}
+		}
 
 		// ファイルの保存
 		if err := os.WriteFile(filename, content, 0644); err != nil {
@@ -169,9 +251,30 @@ func main() {
 		}
 
 		fmt.Printf("   ✅ 保存完了: %s\n", filename)
+		saved = append(saved, filename)
+		fetched[filename] = detail
 		successCount++
 	}
 
+	// 画像と元のURLの対応を保存する
+	if store != nil {
+		if err := store.Save(); err != nil {
+			fmt.Printf("⚠️  %s の保存に失敗しました: %v\n", assets.ManifestFile, err)
+		}
+	}
+
+	// ダウンロードした記事同士のリンクも解決できるよう、保存後にまとめて書き換える
+	if *localLinks && len(saved) > 0 {
+		if err := workspace.LocalizeFiles(client.TeamName(), saved); err != nil {
+			fmt.Printf("⚠️  リンクの書き換えに失敗しました: %v\n", err)
+		}
+	}
+
+	// 3-wayマージのベースを保存し、sync を使っているワークスペースなら書き込んだ内容を同期状態に記録する
+	if err := trackFiles(saved, fetched); err != nil {
+		fmt.Printf("⚠️  同期状態の記録に失敗しました: %v\n", err)
+	}
+
 	// 結果の表示
 	fmt.Println()
 	fmt.Printf("✅ ダウンロード完了 (%d件):\n", successCount)
@@ -182,3 +285,19 @@ func main() {
 		}
 	}
 }
+
+// trackFiles 保存したファイルをワークスペースの同期状態に記録する（sync を実行していなければベースだけを保存し、ワークスペースの外では何も保存しない）
+func trackFiles(paths []string, posts map[string]*types.Post) error {
+	state, err := workspace.LoadWorkspaceState()
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		state.Track(posts[path], path, content)
+	}
+	return state.SaveTracked()
+}
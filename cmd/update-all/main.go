@@ -2,19 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/shellme/esa-cli/internal/api"
 	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/convert"
 	"github.com/shellme/esa-cli/internal/mac"
 	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/naming"
+	"github.com/shellme/esa-cli/internal/workspace"
 	"github.com/shellme/esa-cli/pkg/types"
 	"github.com/spf13/pflag"
 )
@@ -29,7 +32,10 @@ func main() {
 		addTags    = pflag.StringP("add-tags", "a", "", "タグを追加（カンマ区切り）")
 		removeTags = pflag.StringP("remove-tags", "r", "", "タグを削除（カンマ区切り）")
 		force      = pflag.BoolP("force", "f", false, "確認なしで実行")
+		overwrite  = pflag.Bool("overwrite", false, "リモートが更新されていても上書き")
 	)
+	pflag.StringVar(&config.SelectedProfile, "profile", "", "使用するプロファイル（環境変数 ESA_PROFILE でも指定可）")
+	pflag.StringVar(&config.SelectedProfile, "team", "", "使用するプロファイル（--profileの別名）")
 	pflag.Parse()
 
 	// 設定の読み込み
@@ -64,7 +70,44 @@ func main() {
 		os.Exit(1)
 	}
 
+	// プロジェクト設定の ignore に一致するファイルは対象外
+	project := cfg.Project()
+	targets := files[:0]
+	for _, file := range files {
+		if project.Ignored(file) {
+			continue
+		}
+		targets = append(targets, file)
+	}
+	files = targets
+
+	// 同期状態（最後に同期したときの内容のハッシュ）で変更の無いファイルを除く
+	state, err := workspace.LoadWorkspaceState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "同期状態の読み込みに失敗しました: %v\n", err)
+		os.Exit(1)
+	}
+	unchangedCount := 0
+	if *category == "" && *addTags == "" && *removeTags == "" && !*noWip {
+		targets = files[:0]
+		for _, file := range files {
+			if unchangedSinceSync(state, file) {
+				unchangedCount++
+				continue
+			}
+			targets = append(targets, file)
+		}
+		files = targets
+		if unchangedCount > 0 {
+			fmt.Printf("⏭️  最後に同期してから変更の無いファイル %d件はスキップします\n\n", unchangedCount)
+		}
+	}
+
 	if len(files) == 0 {
+		if unchangedCount > 0 {
+			fmt.Println("✅ 更新が必要なファイルはありません。")
+			return
+		}
 		fmt.Println("📭 条件に一致するファイルが見つかりませんでした。")
 		return
 	}
@@ -89,23 +132,38 @@ func main() {
 		fmt.Println()
 	}
 
+	// カテゴリの変更はルートカテゴリ配下として扱う
+	if *category != "" {
+		*category = project.CategoryFor(*category)
+	}
+
 	// 記事の更新
-	successCount := 0
+	successCount, refusedCount := 0, 0
+	links := workspace.NewLinks(client.TeamName())
 	for _, filename := range files {
-		fmt.Printf("📝 更新中: %s\n", filename)
-
-		if err := updateArticle(client, filename, *message, *noWip, *category, *addTags, *removeTags); err != nil {
-			fmt.Printf("   ❌ エラー: %v\n", err)
+		result, err := updateArticle(client, state, links, cfg.GetDefaults(), filename, *message, *noWip, *category, *addTags, *removeTags, *overwrite)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", filename, err)
 			continue
 		}
 
-		fmt.Printf("   ✅ 更新完了: %s\n", filename)
-		successCount++
+		switch result {
+		case resultUnchanged:
+			unchangedCount++
+		case resultRefused:
+			refusedCount++
+		default:
+			successCount++
+		}
 	}
 
 	// 結果の表示
 	fmt.Println()
-	fmt.Printf("✅ 更新完了 (%d件):\n", successCount)
+	fmt.Printf("✅ 更新完了 (%d件) / 変更なし (%d件)\n", successCount, unchangedCount)
+	if refusedCount > 0 {
+		fmt.Printf("⚠️  リモートが更新されていたため %d件をスキップしました\n", refusedCount)
+		fmt.Println("💡 'esa-cli pull' でマージしてから再度実行するか、--overwrite で上書きしてください")
+	}
 	if successCount > 0 {
 		// macOSの場合は通知を表示
 		if err := mac.SendNotification("esa-cli", fmt.Sprintf("%d件の記事を更新しました", successCount)); err != nil {
@@ -114,6 +172,24 @@ func main() {
 	}
 }
 
+// unchangedSinceSync 最後に同期したときからファイルの内容が変わっていないか（同期状態が無ければ false）
+func unchangedSinceSync(state *workspace.State, filename string) bool {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+	fm, _, err := markdown.ParseContent(content)
+	if err != nil {
+		return false
+	}
+	number := fm.Number
+	if number == 0 {
+		number, _ = naming.NumberFromFileName(filename)
+	}
+	entry, ok := state.Entry(number)
+	return ok && entry.Hash == workspace.Hash(content)
+}
+
 // Markdownファイルを検索
 func findMarkdownFiles(pattern string) ([]string, error) {
 	var files []string
@@ -137,24 +213,30 @@ func findMarkdownFiles(pattern string) ([]string, error) {
 
 		// ファイル名を取得
 		filename := filepath.Base(path)
+		if !strings.HasSuffix(filename, ".md") {
+			return nil
+		}
 
-		// パターンマッチング
-		if pattern == "*.md" {
-			// デフォルトパターン: .mdファイルで記事番号-タイトル.mdの形式
-			if strings.HasSuffix(filename, ".md") && isValidArticleFilename(filename) {
-				files = append(files, filename)
-			}
-		} else {
-			// カスタムパターン
+		// パターンマッチング（デフォルトパターンは全ての.mdファイル）
+		if pattern != "*.md" {
 			matched, err := filepath.Match(pattern, filename)
 			if err != nil {
 				return err
 			}
-			if matched && strings.HasSuffix(filename, ".md") && isValidArticleFilename(filename) {
-				files = append(files, filename)
+			if !matched {
+				return nil
 			}
 		}
 
+		// 記事番号-タイトル.mdの形式か、Front Matterに記事番号があるファイルが対象
+		if isValidArticleFilename(filename) || hasPostNumber(path) {
+			rel, err := filepath.Rel(currentDir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+
 		return nil
 	})
 
@@ -168,56 +250,83 @@ func isValidArticleFilename(filename string) bool {
 	return re.MatchString(filename)
 }
 
-// 記事を更新
-func updateArticle(client *api.Client, filename, message string, noWip bool, category, addTags, removeTags string) error {
-	// ファイル名から記事番号を取得
-	postNumberStr := strings.Split(filename, "-")[0]
-	postNumber, err := strconv.Atoi(postNumberStr)
+// Front Matterに記事番号があるか
+func hasPostNumber(path string) bool {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("無効なファイル名です: %s", filename)
+		return false
 	}
+	fm, _, err := markdown.ParseContent(content)
+	return err == nil && fm.Number > 0
+}
 
+// updateResult 記事ごとの更新結果
+type updateResult int
+
+const (
+	resultUpdated updateResult = iota
+	// resultUnchanged 変更が無いため更新しなかった
+	resultUnchanged
+	// resultRefused リモートが更新されていたため更新しなかった
+	resultRefused
+)
+
+// 記事を更新
+func updateArticle(client *api.Client, state *workspace.State, links *workspace.Links, defaults config.Defaults, filename, message string, noWip bool, category, addTags, removeTags string, overwrite bool) (updateResult, error) {
 	// ファイルを読み込む
 	content, err := os.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("ファイルの読み込みに失敗: %v", err)
+		return 0, fmt.Errorf("ファイルの読み込みに失敗: %v", err)
 	}
 
 	// Markdownコンテンツを解析
 	fm, body, err := markdown.ParseContent(content)
 	if err != nil {
-		return fmt.Errorf("ファイルの解析に失敗: %v", err)
+		return 0, fmt.Errorf("ファイルの解析に失敗: %v", err)
+	}
+	if fm.Title == "" {
+		return 0, fmt.Errorf("Front Matterにタイトルがありません")
 	}
 
-	// リモートの更新日時をチェック
-	if fm.RemoteUpdatedAt != "" {
-		remotePost, err := client.FetchPost(context.Background(), postNumber)
-		if err != nil {
-			// 記事が存在しない場合はチェックをスキップ
-			if !strings.Contains(err.Error(), "404") {
-				fmt.Printf("   ⚠️  リモート記事の取得に失敗しました: %v\n", err)
-			}
-		} else {
-			localUpdatedAt, _ := time.Parse(time.RFC3339, fm.RemoteUpdatedAt)
-			if remotePost.UpdatedAt.After(localUpdatedAt) {
-				fmt.Printf("   ⚠️  警告: リモートの記事はローカルで編集を始めてから更新されています。\n")
-				fmt.Printf("      リモート: %s\n", remotePost.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
-				fmt.Printf("      ローカル: %s\n", localUpdatedAt.Local().Format("2006-01-02 15:04:05"))
-				fmt.Print("      このまま上書きしますか？ (y/N): ")
-
-				var confirm string
-				fmt.Scanln(&confirm)
-				if strings.ToLower(confirm) != "y" {
-					return fmt.Errorf("更新を中止しました")
-				}
-			}
+	// 記事番号はFront Matterを優先し、無ければファイル名の先頭から取得
+	postNumber := fm.Number
+	if postNumber == 0 {
+		number, ok := naming.NumberFromFileName(filename)
+		if !ok {
+			return 0, fmt.Errorf("記事番号が分かりません: %s", filename)
 		}
+		postNumber = number
+	}
+	if fm.Team != "" && fm.Team != client.TeamName() {
+		return 0, fmt.Errorf("チーム %s の記事です（現在のチーム: %s）", fm.Team, client.TeamName())
 	}
 
+	entry, _ := state.Entry(postNumber)
+
+	// メッセージの指定が無ければ設定のテンプレートから生成
+	if message == "" {
+		message = defaults.CommitMessage(fm.Title, postNumber, filename, time.Now())
+	}
+
+	remotePost, err := client.FetchPost(context.Background(), postNumber)
+	if errors.Is(err, api.ErrNotFound) {
+		return 0, fmt.Errorf("記事 #%d がリモートにありません（削除された可能性があります）", postNumber)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("リモート記事の取得に失敗: %v", err)
+	}
+
+	// ローカルの画像は元のURLに、ローカルファイルへのリンクは記事リンクに戻してからアップロードする
+	upload, err := convert.PrepareUpload(links, postNumber, body, filepath.Dir(filename))
+	if err != nil {
+		return 0, err
+	}
+	uploadBody := upload.Text
+
 	// 更新リクエストの作成
 	updateReq := types.UpdatePostBody{
 		Name:    fm.Title,
-		BodyMd:  body,
+		BodyMd:  uploadBody,
 		Message: message,
 		Wip:     fm.Wip,
 	}
@@ -252,28 +361,59 @@ func updateArticle(client *api.Client, filename, message string, noWip bool, cat
 		updateReq.Wip = false
 	}
 
+	// リモートと同じ内容なら更新しない
+	summary := workspace.Summary(remotePost, types.FrontMatter{
+		Title:    updateReq.Name,
+		Category: updateReq.Category,
+		Tags:     updateReq.Tags,
+		Wip:      updateReq.Wip,
+	}, updateReq.BodyMd)
+	if summary == "" {
+		return resultUnchanged, nil
+	}
+	if workspace.RemoteMoved(entry, fm, remotePost) && !overwrite {
+		fmt.Printf("⚠️  スキップ: %s（リモートが更新されています / 変更: %s）\n", filename, summary)
+		return resultRefused, nil
+	}
+
 	// 記事の更新
+	fmt.Printf("📝 更新中: %s（%s）\n", filename, summary)
 	updatedPost, err := client.UpdatePost(context.Background(), postNumber, updateReq)
 	if err != nil {
-		return fmt.Errorf("記事の更新に失敗: %v", err)
+		return 0, fmt.Errorf("記事の更新に失敗: %v", err)
 	}
 
 	// ローカルファイルを更新後の内容で書き換える
-	newFm := types.FrontMatter{
-		Title:           updatedPost.Name,
-		Category:        updatedPost.Category,
-		Tags:            updatedPost.Tags,
-		Wip:             updatedPost.Wip,
-		RemoteUpdatedAt: updatedPost.UpdatedAt.Format(time.RFC3339),
-	}
-	newContent, err := markdown.GenerateContent(newFm, updatedPost.BodyMd)
+	newFm := convert.FrontMatter(updatedPost, client.TeamName())
+
+	// カテゴリをディレクトリにした配置では、カテゴリを変更したファイルを新しいカテゴリのディレクトリに移動する
+	target := filename
+	if defaults.TreeLayout() {
+		if to, ok := workspace.CategoryPath(filename, fm.Category, updatedPost.Category); ok {
+			if err := workspace.MoveFile(filename, to, state.Root()); err != nil {
+				fmt.Printf("   ⚠️  ファイルの移動に失敗しました: %v\n", err)
+			} else {
+				fmt.Printf("   🚚 移動: %s → %s\n", filename, to)
+				target = to
+				links.Track(postNumber, to)
+			}
+		}
+	}
+
+	// ローカルの画像やリンクを使っていた場合は書き換えた状態を保つ（移動した場合は移動先を基準にする）
+	localBody := upload.Local(updatedPost.BodyMd, filepath.Dir(target))
+	newContent, err := markdown.UpdateContent(content, newFm, localBody)
 	if err != nil {
-		return fmt.Errorf("ローカルファイルの更新に失敗: %v", err)
+		return 0, fmt.Errorf("ローカルファイルの更新に失敗: %v", err)
 	}
 
-	if err := os.WriteFile(filename, newContent, 0644); err != nil {
-		return fmt.Errorf("ローカルファイルの書き込みに失敗: %v", err)
+	if err := os.WriteFile(target, newContent, 0644); err != nil {
+		return 0, fmt.Errorf("ローカルファイルの書き込みに失敗: %v", err)
+	}
+	if err := workspace.Track(updatedPost, target, newContent); err != nil {
+		fmt.Printf("   ⚠️  同期状態の記録に失敗しました: %v\n", err)
 	}
 
-	return nil
+	fmt.Printf("   ✅ 更新完了: %s\n", target)
+	return resultUpdated, nil
 }
@@ -3,6 +3,16 @@ module github.com/shellme/esa-cli
 go 1.21
 
 require (
-	github.com/spf13/pflag v1.0.6 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
+	github.com/BurntSushi/toml v1.4.0
+	github.com/spf13/pflag v1.0.6
+	github.com/yuin/goldmark v1.7.8
+	github.com/yuin/goldmark-emoji v1.0.4
+	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
+	golang.org/x/text v0.22.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/alecthomas/chroma/v2 v2.2.0 // indirect
+	github.com/dlclark/regexp2 v1.7.0 // indirect
 )
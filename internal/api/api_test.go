@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -183,3 +184,36 @@ func TestClient_UpdatePost(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_DeletePost(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{name: "正常系：記事を削除できる", status: http.StatusNoContent},
+		{name: "異常系：記事が見つからない", status: http.StatusNotFound, wantErr: true, wantNotFound: true},
+		{name: "異常系：権限が無い", status: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := mock.NewMockHTTPClient()
+			mockClient.SetResponse(testutil.CreateMockResponse(t, tt.status, ""), nil)
+			client := NewClient("test-team", "test-token", mockClient)
+
+			err := client.DeletePost(context.Background(), 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeletePost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrNotFound) != tt.wantNotFound {
+				t.Errorf("DeletePost() error = %v, want ErrNotFound %v", err, tt.wantNotFound)
+			}
+			requests := mockClient.GetRequests()
+			if len(requests) != 1 || requests[0].Method != http.MethodDelete || requests[0].URL.Path != "/v1/teams/test-team/posts/1" {
+				t.Errorf("リクエストが不正です: %v", requests)
+			}
+		})
+	}
+}
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/shellme/esa-cli/pkg/types"
 )
 
+// ErrNotFound 記事が存在しない（削除された）
+var ErrNotFound = errors.New("not found")
+
 // ListPostsOptions 記事一覧取得のオプション
 type ListPostsOptions struct {
 	Category string
@@ -44,6 +49,11 @@ func NewClient(teamName, accessToken string, client HTTPDoer) *Client {
 	}
 }
 
+// TeamName 接続先のチーム名
+func (c *Client) TeamName() string {
+	return c.teamName
+}
+
 // 接続テスト
 func (c *Client) TestConnection() error {
 	url := "https://api.esa.io/v1/teams"
@@ -54,10 +64,6 @@ func (c *Client) TestConnection() error {
 
 	req.Header.Set("Authorization", "Bearer "+c.accessToken)
 
-	// デバッグログ
-	fmt.Printf("🔍 リクエストURL: %s\n", url)
-	fmt.Printf("🔍 リクエストヘッダー: %v\n", req.Header)
-
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("ネットワークエラー: %v", err)
@@ -70,11 +76,6 @@ func (c *Client) TestConnection() error {
 		return fmt.Errorf("レスポンスの読み取りに失敗: %v", err)
 	}
 
-	// デバッグログ
-	fmt.Printf("🔍 レスポンスステータス: %d\n", resp.StatusCode)
-	fmt.Printf("🔍 レスポンスヘッダー: %v\n", resp.Header)
-	fmt.Printf("🔍 レスポンスボディ: %s\n", string(body))
-
 	switch resp.StatusCode {
 	case 200:
 		return nil
@@ -87,6 +88,93 @@ func (c *Client) TestConnection() error {
 	}
 }
 
+// TokenInfo アクセストークンの情報（スコープなど）を取得
+func (c *Client) TokenInfo(ctx context.Context) (*types.TokenInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.esa.io/oauth/token/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("認証エラー: アクセストークンが無効です")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var info types.TokenInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetTeam チームの情報を取得
+// 戻り値の time.Time はレスポンスの Date ヘッダー（サーバー時刻、取得できなければゼロ値）
+func (c *Client) GetTeam(ctx context.Context) (*types.Team, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.esa.io/v1/teams/"+c.teamName, nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	serverTime, _ := http.ParseTime(resp.Header.Get("Date"))
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, serverTime, fmt.Errorf("チームが見つかりません: '%s' は存在しないか、アクセス権限がありません", c.teamName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, serverTime, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var team types.Team
+	if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+		return nil, serverTime, err
+	}
+	return &team, serverTime, nil
+}
+
+// GetUser アクセストークンのユーザー情報を取得
+func (c *Client) GetUser(ctx context.Context) (*types.User, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.esa.io/v1/user", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("認証エラー: アクセストークンが無効です")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s", resp.Status)
+	}
+
+	var user types.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (c *Client) makeRequest(method, path string, body io.Reader) (*http.Response, error) {
 	url := fmt.Sprintf("https://api.esa.io/v1%s", path)
 	req, err := http.NewRequest(method, url, body)
@@ -184,7 +272,7 @@ func (c *Client) FetchPost(ctx context.Context, postNum int) (*types.Post, error
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("not found")
+		return nil, ErrNotFound
 	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API error: %s", resp.Status)
@@ -213,6 +301,9 @@ func (c *Client) UpdatePost(ctx context.Context, postNumber int, post types.Upda
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned status: %s", resp.Status)
 	}
@@ -253,6 +344,25 @@ func (c *Client) CreatePost(ctx context.Context, post types.CreatePostBody) (*ty
 	return &createdPost, nil
 }
 
+// DeletePost 記事を削除
+func (c *Client) DeletePost(ctx context.Context, postNumber int) error {
+	path := fmt.Sprintf("/teams/%s/posts/%d", c.teamName, postNumber)
+
+	resp, err := c.makeRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API returned status: %s", resp.Status)
+	}
+	return nil
+}
+
 // BulkUpdateCategory 複数の記事のカテゴリを一括更新
 func (c *Client) BulkUpdateCategory(ctx context.Context, postNumbers []int, newCategory string, message string) ([]*types.Post, error) {
 	var updatedPosts []*types.Post
@@ -270,23 +380,3 @@ func (c *Client) BulkUpdateCategory(ctx context.Context, postNumbers []int, newC
 
 	return updatedPosts, nil
 }
-
-func sanitizeFilename(name string) string {
-	// ファイル名に使えない文字を置換
-	replacements := map[string]string{
-		"/": "-", "\\": "-", ":": "-", "*": "-",
-		"?": "-", "\"": "-", "<": "-", ">": "-", "|": "-",
-	}
-
-	result := name
-	for old, new := range replacements {
-		result = strings.ReplaceAll(result, old, new)
-	}
-
-	// 長すぎる場合は切り詰め
-	if len(result) > 100 {
-		result = result[:100]
-	}
-
-	return result
-}
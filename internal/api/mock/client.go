@@ -13,6 +13,7 @@ type MockHTTPClient struct {
 	requests []*http.Request
 	response *http.Response
 	err      error
+	handler  func(req *http.Request) (*http.Response, error)
 }
 
 // NewMockHTTPClient モックHTTPクライアントを作成
@@ -28,6 +29,13 @@ func (m *MockHTTPClient) SetResponse(resp *http.Response, err error) {
 	m.err = err
 }
 
+// SetHandler リクエストごとにレスポンスを返す関数を設定（SetResponseより優先）
+func (m *MockHTTPClient) SetHandler(handler func(req *http.Request) (*http.Response, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handler = handler
+}
+
 // GetRequests リクエスト履歴を取得
 func (m *MockHTTPClient) GetRequests() []*http.Request {
 	m.mu.Lock()
@@ -40,6 +48,9 @@ func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.requests = append(m.requests, req)
+	if m.handler != nil {
+		return m.handler(req)
+	}
 	return m.response, m.err
 }
 
@@ -0,0 +1,250 @@
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/workspace"
+)
+
+const (
+	// DirName 画像を保存するディレクトリ名（ワークスペースのルート直下）
+	DirName = "assets"
+	// ManifestFile 元のURLとの対応を記録するファイル名
+	ManifestFile = "manifest.json"
+)
+
+// Manifest ローカルの画像ファイルと元のURLの対応
+// 同じ内容の画像が別のURLでアップロードされていても元に戻せるよう、記事ごとに本文中の出現順で記録する
+type Manifest struct {
+	// Posts 記事番号 → 本文中の画像
+	Posts map[string][]Asset `json:"posts"`
+}
+
+// Asset 本文中の画像1つ分の記録
+type Asset struct {
+	File string `json:"file"`
+	URL  string `json:"url"`
+}
+
+// Store assetsディレクトリ
+type Store struct {
+	dir      string
+	client   api.HTTPDoer
+	manifest Manifest
+	// files 元のURL → 画像ファイル名（ダウンロード済みの画像を再利用する）
+	files map[string]string
+}
+
+// Open assetsディレクトリを開く（manifest.json があれば読み込む）
+func Open(dir string, client api.HTTPDoer) (*Store, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		dir:      dir,
+		client:   client,
+		manifest: Manifest{Posts: map[string][]Asset{}},
+		files:    map[string]string{},
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &s.manifest); err != nil {
+			return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", ManifestFile, err)
+		}
+		if s.manifest.Posts == nil {
+			s.manifest.Posts = map[string][]Asset{}
+		}
+	}
+	for _, list := range s.manifest.Posts {
+		for _, a := range list {
+			if _, err := os.Stat(filepath.Join(dir, a.File)); err == nil {
+				s.files[a.URL] = a.File
+			}
+		}
+	}
+	return s, nil
+}
+
+// OpenWorkspace ワークスペースのルート直下の assets ディレクトリを開く
+func OpenWorkspace(client api.HTTPDoer) (*Store, error) {
+	root, err := workspace.Root()
+	if err != nil {
+		return nil, err
+	}
+	return Open(filepath.Join(root, DirName), client)
+}
+
+// Dir assetsディレクトリのパス
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// IsAttachment esa.ioにアップロードされた添付ファイルのURLか
+func IsAttachment(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return false
+	}
+	return (u.Host == "esa.io" || strings.HasSuffix(u.Host, ".esa.io")) && strings.Contains(u.Path, "/uploads/")
+}
+
+// Localize 本文中の添付ファイルをダウンロードし、ローカルのパスに書き換える
+// fromDir は本文を書き込むファイルのディレクトリ。ダウンロードに失敗した画像は元のURLのまま残す
+// 記事の記録は本文の内容で置き換える
+func (s *Store) Localize(ctx context.Context, number int, body, fromDir string) (string, []error) {
+	fromDir, _ = filepath.Abs(fromDir)
+	key := strconv.Itoa(number)
+	delete(s.manifest.Posts, key)
+	var errs []error
+	body = markdown.RewriteLinks(body, func(dest string) string {
+		if !IsAttachment(dest) {
+			return dest
+		}
+		name, err := s.fetch(ctx, dest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", dest, err))
+			return dest
+		}
+		s.manifest.Posts[key] = append(s.manifest.Posts[key], Asset{File: name, URL: dest})
+		s.files[dest] = name
+
+		rel, err := filepath.Rel(fromDir, filepath.Join(s.dir, name))
+		if err != nil {
+			return dest
+		}
+		return filepath.ToSlash(rel)
+	})
+	return body, errs
+}
+
+// Restore ローカルの画像パスを manifest.json に記録された元のURLに戻す
+// 同じファイルが複数のURLに対応する場合は、記録した出現順にURLを割り当てる。記録の無い画像はそのまま残す
+func (s *Store) Restore(number int, body, fromDir string) string {
+	list := s.manifest.Posts[strconv.Itoa(number)]
+	if len(list) == 0 {
+		return body
+	}
+	fromDir, _ = filepath.Abs(fromDir)
+	used := make([]bool, len(list))
+	return markdown.RewriteLinks(body, func(dest string) string {
+		if strings.Contains(dest, "://") {
+			return dest
+		}
+		p := dest
+		if unescaped, err := url.PathUnescape(p); err == nil {
+			p = unescaped
+		}
+		abs := filepath.Join(fromDir, filepath.FromSlash(p))
+		if filepath.Dir(abs) != s.dir {
+			return dest
+		}
+		return restoreURL(list, used, filepath.Base(abs), dest)
+	})
+}
+
+// restoreURL ファイル名に対応するURLのうち、まだ使っていない最初のものを返す
+// すべて使い切っていれば最後に対応したURLを返す
+func restoreURL(list []Asset, used []bool, name, dest string) string {
+	last := ""
+	for i, a := range list {
+		if a.File != name {
+			continue
+		}
+		if !used[i] {
+			used[i] = true
+			return a.URL
+		}
+		last = a.URL
+	}
+	if last != "" {
+		return last
+	}
+	return dest
+}
+
+// Save manifest.json を保存する
+func (s *Store) Save() error {
+	manifest := filepath.Join(s.dir, ManifestFile)
+	if len(s.manifest.Posts) == 0 {
+		if _, err := os.Stat(manifest); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(&s.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifest, append(data, '\n'), 0644)
+}
+
+// fetch 画像をダウンロードし、内容のハッシュをファイル名として保存する
+func (s *Store) fetch(ctx context.Context, u string) (string, error) {
+	if name, ok := s.files[u]; ok {
+		return name, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])[:16] + extension(u)
+	target := filepath.Join(s.dir, name)
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		if err := os.MkdirAll(s.dir, 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// extension URLのパスから拡張子を取得する
+func extension(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	ext := strings.ToLower(path.Ext(u.Path))
+	if len(ext) > 6 {
+		return ""
+	}
+	return ext
+}
@@ -0,0 +1,129 @@
+package assets
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/api/mock"
+)
+
+// newMockClient URLごとに画像の内容を返すモッククライアント
+func newMockClient(files map[string]string) *mock.MockHTTPClient {
+	client := mock.NewMockHTTPClient()
+	client.SetHandler(func(req *http.Request) (*http.Response, error) {
+		data, ok := files[req.URL.String()]
+		if !ok {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: mock.NewReadCloser(nil)}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: mock.NewReadCloser([]byte(data))}, nil
+	})
+	return client
+}
+
+func TestIsAttachment(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{"正常系：チームのアップロードファイル", "https://img.esa.io/uploads/production/attachments/1/2025/01/01/1/abc.png", true},
+		{"正常系：files.esa.io", "https://files.esa.io/uploads/production/attachments/1/abc.pdf", true},
+		{"異常系：記事のURL", "https://docs.esa.io/posts/1", false},
+		{"異常系：外部の画像", "https://example.com/uploads/a.png", false},
+		{"異常系：ローカルのパス", "../assets/a.png", false},
+		{"エッジケース：ホスト名の一部がesa.io", "https://evilesa.io/uploads/a.png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAttachment(tt.url); got != tt.want {
+				t.Errorf("IsAttachment(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_LocalizeAndRestore(t *testing.T) {
+	root := t.TempDir()
+	postDir := filepath.Join(root, "docs")
+	urlA := "https://img.esa.io/uploads/production/attachments/1/a.png"
+	urlB := "https://img.esa.io/uploads/production/attachments/1/b.PNG"
+	urlMissing := "https://img.esa.io/uploads/production/attachments/1/missing.png"
+	client := newMockClient(map[string]string{urlA: "same image", urlB: "same image"})
+
+	store, err := Open(filepath.Join(root, DirName), client)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	body := "![a](" + urlA + ")\n" +
+		"<img src=\"" + urlB + "\" width=\"100\">\n" +
+		"![missing](" + urlMissing + ")\n" +
+		"`![code](" + urlA + ")`\n"
+	localized, errs := store.Localize(context.Background(), 1, body, postDir)
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), urlMissing) {
+		t.Errorf("Localize() errs = %v, want 1 error for %s", errs, urlMissing)
+	}
+	entries, _ := os.ReadDir(store.Dir())
+	if len(entries) != 1 {
+		t.Fatalf("同じ内容の画像は1ファイルにまとめる: got %d files", len(entries))
+	}
+	rel := "../assets/" + entries[0].Name()
+	if !strings.HasSuffix(rel, ".png") {
+		t.Errorf("拡張子は小文字のURLの拡張子を使う: %s", rel)
+	}
+	want := "![a](" + rel + ")\n" +
+		"<img src=\"" + rel + "\" width=\"100\">\n" +
+		"![missing](" + urlMissing + ")\n" +
+		"`![code](" + urlA + ")`\n"
+	if localized != want {
+		t.Errorf("Localize() =\n%s\nwant\n%s", localized, want)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// 保存した manifest.json から元のURLに戻せる（同じ内容のファイルも出現順にそれぞれのURLに戻る）
+	reopened, err := Open(filepath.Join(root, DirName), newMockClient(nil))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	restored := reopened.Restore(1, localized, postDir)
+	wantRestored := "![a](" + urlA + ")\n" +
+		"<img src=\"" + urlB + "\" width=\"100\">\n" +
+		"![missing](" + urlMissing + ")\n" +
+		"`![code](" + urlA + ")`\n"
+	if restored != wantRestored {
+		t.Errorf("Restore() =\n%s\nwant\n%s", restored, wantRestored)
+	}
+
+	// 別の記事の画像として記録されていなければ書き換えない
+	if got := reopened.Restore(2, localized, postDir); got != localized {
+		t.Errorf("Restore() for another post = %q, want unchanged", got)
+	}
+
+	// ダウンロード済みの画像は再ダウンロードしない
+	again, errs := reopened.Localize(context.Background(), 1, "![a]("+urlA+")\n", postDir)
+	if len(errs) != 0 || again != "![a]("+rel+")\n" {
+		t.Errorf("Localize() with manifest = %q, %v", again, errs)
+	}
+}
+
+func TestStore_SaveWithoutAssets(t *testing.T) {
+	root := t.TempDir()
+	store, err := Open(filepath.Join(root, DirName), newMockClient(nil))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, DirName)); !os.IsNotExist(err) {
+		t.Errorf("画像が無ければ assets ディレクトリを作成しない")
+	}
+}
@@ -3,40 +3,163 @@ package config
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/shellme/esa-cli/internal/api"
 )
 
-type Config struct {
-	AccessToken string `json:"access_token"`
+// Profile チームごとの接続情報
+type Profile struct {
+	AccessToken string `json:"access_token,omitempty"`
 	TeamName    string `json:"team_name"`
+
+	// CredentialHelper トークンを取得する外部コマンド（未指定なら全体の設定を使う）
+	CredentialHelper string `json:"credential_helper,omitempty"`
+}
+
+type Config struct {
+	// 選択中のプロファイルの接続情報（Load時に解決される）
+	AccessToken string `json:"access_token,omitempty"`
+	TeamName    string `json:"team_name,omitempty"`
+
+	DefaultProfile string              `json:"default_profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles,omitempty"`
+
+	// CredentialHelper 全プロファイル共通のクレデンシャルヘルパー
+	CredentialHelper string `json:"credential_helper,omitempty"`
+
+	// Defaults コマンドのデフォルト値
+	Defaults *Defaults `json:"defaults,omitempty"`
+
+	// ActiveProfile Load時に選択されたプロファイル名
+	ActiveProfile string `json:"-"`
+
+	path    string
+	origins map[string]Origin
+	project *ProjectConfig
 }
 
 // 設定ファイルのパス
 var (
 	ConfigDir  string
 	ConfigFile string
+
+	// SelectedProfile --profile/--team フラグで指定されたプロファイル
+	SelectedProfile string
+)
+
+// 設定を上書きする環境変数
+const (
+	ProfileEnv = "ESA_PROFILE"
+	TeamEnv    = "ESA_TEAM"
+	TokenEnv   = "ESA_ACCESS_TOKEN"
 )
 
 // 設定ファイルのパスを取得
+// $XDG_CONFIG_HOME/esa-cli/config.json を優先し、無ければ従来の ~/.esa-cli-config.json を使う
 func getConfigPath() string {
 	if ConfigFile != "" {
 		return ConfigFile
 	}
-	homeDir, _ := os.UserHomeDir()
+
+	xdgPath := xdgConfigPath()
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath
+	}
+	if legacyPath := legacyConfigPath(); legacyPath != "" {
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath
+		}
+	}
+	return xdgPath
+}
+
+// xdgConfigPath XDG Base Directory に従った設定ファイルのパス
+func xdgConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dir = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(dir, "esa-cli", "config.json")
+}
+
+// legacyConfigPath 従来の設定ファイルのパス
+func legacyConfigPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
 	return filepath.Join(homeDir, ".esa-cli-config.json")
 }
 
+// Path 使用される設定ファイルのパスを返す
+func Path() string {
+	return getConfigPath()
+}
+
+// CandidatePaths 設定ファイルの探索候補を優先順に返す
+func CandidatePaths() []string {
+	if ConfigFile != "" {
+		return []string{ConfigFile}
+	}
+	paths := []string{xdgConfigPath()}
+	if legacyPath := legacyConfigPath(); legacyPath != "" {
+		paths = append(paths, legacyPath)
+	}
+	return paths
+}
+
 // 設定を読み込み
+// 値の優先順位は フラグ > 環境変数 > プロジェクト設定 > ユーザー設定
 func Load() (*Config, error) {
+	config, err := loadFile()
+	if err != nil {
+		// 環境変数だけで接続情報が揃う場合は設定ファイルが無くてもよい
+		if !errors.Is(err, os.ErrNotExist) || os.Getenv(TokenEnv) == "" {
+			return nil, err
+		}
+		config = &Config{}
+	}
+
+	project, err := LoadProjectConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.project = project
+
+	if err := config.resolve(project); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// notFoundError 設定ファイルが存在しないことを表すエラー
+type notFoundError struct {
+	path string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("設定ファイルが見つかりません: %s", e.path)
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == os.ErrNotExist
+}
+
+// loadFile 設定ファイルを読み込み、旧形式であればプロファイル形式へ移行する
+func loadFile() (*Config, error) {
 	configPath := getConfigPath()
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("設定ファイルが見つかりません: %s", configPath)
+		return nil, &notFoundError{path: configPath}
 	}
 
 	data, err := os.ReadFile(configPath)
@@ -48,22 +171,98 @@ func Load() (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	config.path = configPath
+
+	if config.migrate() {
+		// 移行結果の書き戻しに失敗しても読み込み自体は継続する
+		_ = Save(&config)
+	}
 
 	return &config, nil
 }
 
+// migrate 単一チーム形式の設定をプロファイル形式へ移行する
+func (c *Config) migrate() bool {
+	if len(c.Profiles) > 0 || c.TeamName == "" {
+		return false
+	}
+	c.Profiles = map[string]*Profile{
+		c.TeamName: {AccessToken: c.AccessToken, TeamName: c.TeamName},
+	}
+	if c.DefaultProfile == "" {
+		c.DefaultProfile = c.TeamName
+	}
+	return true
+}
+
+// findProfile プロファイル名、またはチーム名でプロファイルを検索する
+func (c *Config) findProfile(name string) (string, *Profile) {
+	if p, ok := c.Profiles[name]; ok {
+		return name, p
+	}
+	for n, p := range c.Profiles {
+		if p.TeamName == name {
+			return n, p
+		}
+	}
+	return "", nil
+}
+
+// ProfileNames プロファイル名をソートして返す
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // 設定を保存
 func Save(config *Config) error {
 	if config == nil {
 		return fmt.Errorf("設定がnilです")
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	// 有効なプロファイルの接続情報をプロファイル側へ反映する
+	out := *config
+	out.Profiles = make(map[string]*Profile, len(config.Profiles))
+	for name, p := range config.Profiles {
+		copied := *p
+		out.Profiles[name] = &copied
+	}
+	if out.TeamName != "" || out.AccessToken != "" {
+		name := out.ActiveProfile
+		if name == "" {
+			name = out.TeamName
+		}
+		profile, ok := out.Profiles[name]
+		if !ok {
+			profile = &Profile{}
+			out.Profiles[name] = profile
+		}
+		profile.TeamName = out.TeamName
+		// 環境変数やクレデンシャルヘルパーから得たトークンは平文で保存しない
+		if src := out.origins["access_token"].Source; src != OriginEnv && src != OriginCredentialHelper {
+			profile.AccessToken = out.AccessToken
+		}
+		if out.DefaultProfile == "" {
+			out.DefaultProfile = name
+		}
+	}
+	out.TeamName = ""
+	out.AccessToken = ""
+
+	data, err := json.MarshalIndent(&out, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(getConfigPath(), data, 0600)
+	configPath := getConfigPath()
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0600)
 }
 
 // APIクライアントのインターフェース
@@ -71,41 +270,88 @@ type APIClient interface {
 	TestConnection() error
 }
 
+// SetupOptions 初期設定のオプション
+type SetupOptions struct {
+	// TeamName チーム名（空なら対話形式で入力）
+	TeamName string
+	// TokenReader トークンを読み込む入力（--token-stdin、nilなら対話形式で入力）
+	// 標準入力はトークンに使うため、TeamName の指定が必要
+	TokenReader io.Reader
+	// NoVerify 接続テストを行わない
+	NoVerify bool
+}
+
+// interactive 対話形式の入力が必要か
+func (o SetupOptions) interactive() bool {
+	return o.TeamName == "" || o.TokenReader == nil
+}
+
 // 初期設定コマンド
 func Setup(client APIClient) error {
+	return SetupWithOptions(client, SetupOptions{})
+}
+
+// SetupWithOptions オプションを指定して初期設定を行う
+func SetupWithOptions(client APIClient, opts SetupOptions) error {
+	// チーム名を対話形式で読むとトークンの入力まで読み込んでしまうため、同時には使えない
+	if opts.TokenReader != nil && opts.TeamName == "" {
+		return fmt.Errorf("--token-stdin を使う場合は --team でチーム名を指定してください")
+	}
+
 	// 初期設定時は設定ファイルが存在しなくても正常
 	config := &Config{}
 
 	// 既存の設定ファイルがある場合は読み込み
-	if existingConfig, err := Load(); err == nil {
+	// 環境変数による上書きを保存しないよう、ファイルの内容だけを使う
+	if existingConfig, err := loadFile(); err == nil {
 		config = existingConfig
 	}
 
-	fmt.Println("🔧 esa-cli 初期設定")
-	fmt.Println("")
-	fmt.Println("📋 以下の手順でアクセストークンを取得してください：")
-	fmt.Println("1. https://{your-team}.esa.io/user/applications にアクセス")
-	fmt.Println("2. 'Personal access tokens' セクションの 'Generate new token' をクリック")
-	fmt.Println("3. Token description に 'esa-cli' と入力")
-	fmt.Println("4. Scopes で 'read' と 'write' にチェック")
-	fmt.Println("5. 'Generate token' をクリック")
-	fmt.Println("6. 表示されたトークンをコピー（画面を閉じると再表示できません）")
-	fmt.Println("")
+	if opts.interactive() {
+		fmt.Println("🔧 esa-cli 初期設定")
+		fmt.Println("")
+		fmt.Println("📋 以下の手順でアクセストークンを取得してください：")
+		fmt.Println("1. https://{your-team}.esa.io/user/applications にアクセス")
+		fmt.Println("2. 'Personal access tokens' セクションの 'Generate new token' をクリック")
+		fmt.Println("3. Token description に 'esa-cli' と入力")
+		fmt.Println("4. Scopes で 'read' と 'write' にチェック")
+		fmt.Println("5. 'Generate token' をクリック")
+		fmt.Println("6. 表示されたトークンをコピー（画面を閉じると再表示できません）")
+		fmt.Println("")
+	}
 
 	scanner := bufio.NewScanner(os.Stdin)
 
-	fmt.Print("チーム名（サブドメイン）を入力: ")
-	if scanner.Scan() {
-		config.TeamName = strings.TrimSpace(scanner.Text())
+	config.TeamName = opts.TeamName
+	if config.TeamName == "" {
+		fmt.Print("チーム名（サブドメイン）を入力: ")
+		if scanner.Scan() {
+			config.TeamName = strings.TrimSpace(scanner.Text())
+		}
 	}
 
-	fmt.Print("アクセストークンを入力: ")
-	if scanner.Scan() {
-		config.AccessToken = strings.TrimSpace(scanner.Text())
+	if opts.TokenReader != nil {
+		data, err := io.ReadAll(opts.TokenReader)
+		if err != nil {
+			return fmt.Errorf("アクセストークンの読み込みに失敗しました: %v", err)
+		}
+		config.AccessToken = strings.TrimSpace(string(data))
+	} else {
+		fmt.Print("アクセストークンを入力: ")
+		if scanner.Scan() {
+			config.AccessToken = strings.TrimSpace(scanner.Text())
+		}
 	}
 
-	// デバッグログ（開発時のみ）
-	fmt.Printf("🔍 デバッグ: チーム名='%s', トークン='%s'\n", config.TeamName, config.AccessToken)
+	// 保存先のプロファイルを決定（同じチームのプロファイルがあれば上書き）
+	config.ActiveProfile = SelectedProfile
+	if config.ActiveProfile == "" {
+		if name, _ := config.findProfile(config.TeamName); name != "" {
+			config.ActiveProfile = name
+		} else {
+			config.ActiveProfile = config.TeamName
+		}
+	}
 
 	// 入力値の検証
 	if config.TeamName == "" {
@@ -115,14 +361,25 @@ func Setup(client APIClient) error {
 		return fmt.Errorf("アクセストークンが入力されていません")
 	}
 
-	// 設定をテスト
-	fmt.Println("")
-	fmt.Println("🧪 設定をテスト中...")
+	if !opts.NoVerify {
+		// 設定をテスト
+		fmt.Println("")
+		fmt.Println("🧪 設定をテスト中...")
 
-	// 入力値で新しいクライアントを生成
-	client = api.NewClient(config.TeamName, config.AccessToken, http.DefaultClient)
-	if err := client.TestConnection(); err != nil {
-		return fmt.Errorf("接続テストに失敗しました: %v\n\nトークンやチーム名を確認してください", err)
+		// 入力値で新しいクライアントを生成
+		client = api.NewClient(config.TeamName, config.AccessToken, http.DefaultClient)
+		if err := client.TestConnection(); err != nil {
+			return fmt.Errorf("接続テストに失敗しました: %v\n\nトークンやチーム名を確認してください", err)
+		}
+	}
+
+	// クレデンシャルヘルパーが設定されていればトークンはそちらに保存する
+	if helper := config.credentialHelper(config.ActiveProfile); helper != nil {
+		if err := helper.Store(config.TeamName, config.AccessToken); err != nil {
+			fmt.Printf("⚠️  クレデンシャルヘルパーへの保存に失敗しました。設定ファイルに保存します: %v\n", err)
+		} else {
+			config.origins = map[string]Origin{"access_token": {Source: OriginCredentialHelper, Detail: helper.Command}}
+		}
 	}
 
 	if err := Save(config); err != nil {
@@ -130,6 +387,9 @@ func Setup(client APIClient) error {
 	}
 
 	fmt.Println("✅ 設定が完了しました！")
+	if !opts.interactive() {
+		return nil
+	}
 	fmt.Println("")
 	fmt.Println("🚀 使用方法:")
 	fmt.Println("  esa-cli fetch 123      # 記事番号123をダウンロード")
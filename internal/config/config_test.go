@@ -3,6 +3,8 @@ package config
 import (
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/shellme/esa-cli/internal/api"
@@ -179,6 +181,75 @@ func TestSetup(t *testing.T) {
 	}
 }
 
+func TestSetupWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SetupOptions
+		wantErr bool
+	}{
+		{
+			name: "正常系：チーム名と標準入力のトークンで非対話に設定できる",
+			opts: SetupOptions{
+				TeamName:    "ci-team",
+				TokenReader: strings.NewReader("ci-token\n"),
+				NoVerify:    true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "異常系：トークンが空の場合",
+			opts: SetupOptions{
+				TeamName:    "ci-team",
+				TokenReader: strings.NewReader("\n"),
+				NoVerify:    true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系：標準入力のトークンでチーム名を指定しない場合",
+			opts: SetupOptions{
+				TokenReader: strings.NewReader("ci-token\n"),
+				NoVerify:    true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: 空の設定ディレクトリ
+			tmpDir := testutil.CreateTempDir(t)
+			ConfigFile = filepath.Join(tmpDir, "config.json")
+
+			// When
+			err := SetupWithOptions(nil, tt.opts)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetupWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			cfg, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.TeamName != "ci-team" || cfg.AccessToken != "ci-token" {
+				t.Errorf("SetupWithOptions() saved = %+v", cfg)
+			}
+			info, err := os.Stat(ConfigFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("config file mode = %v, want 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
 // 異常系：nilのクライアント専用のテスト
 func TestSetup_NilClient(t *testing.T) {
 	err := Setup(nil)
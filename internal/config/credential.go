@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CredentialHelper git-credential 形式でトークンを取得・保存する外部コマンド
+//
+// 引数の無いコマンド（例: "esa-credential-vault"）と git のヘルパー（例: "git credential-osxkeychain"）には
+// get/store/erase のいずれかが引数として追加され、標準入力には key=value 形式（protocol, host, username,
+// 保存時は password）が渡される。コマンド中の {action} は get/store/erase に置き換える。
+// get では標準出力の password= 行、または key=value 形式でなければ最初の行をトークンとして扱う。
+// それ以外の引数付きのコマンド（例: "pass show esa/myteam"）と先頭が "!" のコマンドは
+// 引数を追加せずにそのまま実行し、get のみに対応する。
+type CredentialHelper struct {
+	Command string
+}
+
+// credentialHelper プロファイルに対応するクレデンシャルヘルパーを返す
+func (c *Config) credentialHelper(profileName string) *CredentialHelper {
+	command := c.CredentialHelper
+	if p, ok := c.Profiles[profileName]; ok && p.CredentialHelper != "" {
+		command = p.CredentialHelper
+	}
+	if command == "" {
+		return nil
+	}
+	return &CredentialHelper{Command: command}
+}
+
+// Get チームのトークンを取得
+func (h *CredentialHelper) Get(teamName string) (string, error) {
+	out, err := h.run("get", teamName, "")
+	if err != nil {
+		return "", err
+	}
+
+	var firstLine string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if firstLine == "" {
+			firstLine = line
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && (key == "password" || key == "token") {
+			return value, nil
+		}
+	}
+
+	// key=value 形式で返さないコマンド（pass など）は最初の行をトークンとみなす
+	if firstLine != "" && !strings.Contains(firstLine, "=") {
+		return firstLine, nil
+	}
+	return "", nil
+}
+
+// Store チームのトークンを保存
+func (h *CredentialHelper) Store(teamName, token string) error {
+	if h.readOnly() {
+		return fmt.Errorf("このクレデンシャルヘルパーは保存に対応していません: %s", h.Command)
+	}
+	_, err := h.run("store", teamName, token)
+	return err
+}
+
+// Erase チームのトークンを削除
+func (h *CredentialHelper) Erase(teamName string) error {
+	if h.readOnly() {
+		return fmt.Errorf("このクレデンシャルヘルパーは削除に対応していません: %s", h.Command)
+	}
+	_, err := h.run("erase", teamName, "")
+	return err
+}
+
+// readOnly トークンを取得するだけのコマンドか
+func (h *CredentialHelper) readOnly() bool {
+	if strings.HasPrefix(h.Command, "!") {
+		return true
+	}
+	if strings.Contains(h.Command, "{action}") {
+		return false
+	}
+	fields := strings.Fields(h.Command)
+	return len(fields) > 1 && fields[0] != "git"
+}
+
+func (h *CredentialHelper) run(action, teamName, token string) (string, error) {
+	command := strings.TrimPrefix(h.Command, "!")
+	switch {
+	case strings.Contains(command, "{action}"):
+		command = strings.ReplaceAll(command, "{action}", action)
+	case !h.readOnly():
+		command += " " + action
+	}
+
+	var input bytes.Buffer
+	fmt.Fprintf(&input, "protocol=https\n")
+	fmt.Fprintf(&input, "host=%s.esa.io\n", teamName)
+	fmt.Fprintf(&input, "username=%s\n", teamName)
+	if token != "" {
+		fmt.Fprintf(&input, "password=%s\n", token)
+	}
+	input.WriteString("\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = &input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "ESA_TEAM="+teamName)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s %s: %v: %s", h.Command, action, err, msg)
+		}
+		return "", fmt.Errorf("%s %s: %v", h.Command, action, err)
+	}
+
+	return stdout.String(), nil
+}
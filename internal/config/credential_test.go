@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/testutil"
+)
+
+// createHelperScript ファイルにトークンを保存するテスト用のヘルパーを作成
+func createHelperScript(t *testing.T, dir string) string {
+	t.Helper()
+	script := filepath.Join(dir, "helper.sh")
+	store := filepath.Join(dir, "store")
+	content := `#!/bin/sh
+input=$(cat)
+host=$(echo "$input" | sed -n 's/^host=//p')
+case "$1" in
+get)
+  if [ -f "` + store + `/$host" ]; then echo "password=$(cat "` + store + `/$host")"; fi
+  ;;
+store)
+  mkdir -p "` + store + `"
+  echo "$input" | sed -n 's/^password=//p' > "` + store + `/$host"
+  ;;
+erase)
+  rm -f "` + store + `/$host"
+  ;;
+esac
+`
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestCredentialHelper(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	helper := &CredentialHelper{Command: createHelperScript(t, tmpDir)}
+
+	// Given: トークンを保存
+	if err := helper.Store("test-team", "secret-token"); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	// When: トークンを取得
+	got, err := helper.Get("test-team")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != "secret-token" {
+		t.Errorf("Get() = %v, want secret-token", got)
+	}
+
+	// When: トークンを削除
+	if err := helper.Erase("test-team"); err != nil {
+		t.Fatalf("Erase() error = %v", err)
+	}
+	got, err = helper.Get("test-team")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("Get() after Erase = %v, want empty", got)
+	}
+}
+
+func TestCredentialHelper_PlainCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		want     string
+		wantErr  bool
+		storeErr bool
+	}{
+		{
+			name:     "正常系：!付きのコマンドは出力の1行目をトークンとする",
+			command:  "!printf 'plain-token\\nsecond line\\n'",
+			want:     "plain-token",
+			storeErr: true,
+		},
+		{
+			name:     "正常系：引数付きのコマンドは引数を追加せずに実行する（pass show esa/myteam）",
+			command:  "pass show esa/myteam",
+			want:     "pass-token",
+			storeErr: true,
+		},
+		{
+			name:    "正常系：{action} を get/store/erase に置き換える",
+			command: "echo-action {action} --team",
+			want:    "get --team",
+		},
+		{
+			name:    "異常系：コマンドが失敗した場合",
+			command: "!exit 1",
+			wantErr: true,
+		},
+	}
+
+	// テスト用の pass と、引数をそのまま出力するコマンド
+	bin := testutil.CreateTempDir(t)
+	scripts := map[string]string{
+		"pass":        "#!/bin/sh\n[ \"$1 $2\" = \"show esa/myteam\" ] && [ $# -eq 2 ] && echo pass-token\n",
+		"echo-action": "#!/bin/sh\necho \"$@\"\n",
+	}
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := &CredentialHelper{Command: tt.command}
+			got, err := helper.Get("test-team")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+			if err := helper.Store("test-team", "x"); (err != nil) != tt.storeErr && !tt.wantErr {
+				t.Errorf("Store() error = %v, wantErr %v", err, tt.storeErr)
+			}
+		})
+	}
+}
+
+func TestLoad_CredentialHelper(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+	script := createHelperScript(t, tmpDir)
+
+	// Given: ヘルパー付きでプロファイルを追加
+	if err := AddProfile("work", "work-team", "helper-token", script); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+
+	// トークンが平文で保存されていないこと
+	data, err := os.ReadFile(ConfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "helper-token") {
+		t.Errorf("token is stored in plaintext: %s", data)
+	}
+
+	// When: 読み込み
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.AccessToken != "helper-token" {
+		t.Errorf("Load() AccessToken = %v, want helper-token", cfg.AccessToken)
+	}
+	if cfg.Origin("access_token").Source != OriginCredentialHelper {
+		t.Errorf("Origin(access_token) = %v, want credential_helper", cfg.Origin("access_token"))
+	}
+
+	// 保存し直してもトークンは平文で書き出されない
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(ConfigFile)
+	if strings.Contains(string(data), "helper-token") {
+		t.Errorf("token is stored in plaintext after Save: %s", data)
+	}
+}
+
+func TestLoad_CredentialHelperFallback(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+
+	// Given: 失敗するヘルパーと平文のトークン
+	data := `{"credential_helper": "!exit 1", "profiles": {"work": {"team_name": "work-team", "access_token": "plain-token"}}}`
+	if err := os.WriteFile(ConfigFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.AccessToken != "plain-token" {
+		t.Errorf("Load() AccessToken = %v, want plain-token", cfg.AccessToken)
+	}
+}
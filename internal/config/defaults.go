@@ -0,0 +1,374 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/naming"
+)
+
+// Defaults フラグが指定されなかったときにコマンドが使うデフォルト値
+type Defaults struct {
+	Category         string   `json:"category,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	MessageTemplate  string   `json:"message_template,omitempty"`
+	OutputDir        string   `json:"output_dir,omitempty"`
+	FilenameTemplate string   `json:"filename_template,omitempty"`
+	Wip              *bool    `json:"wip,omitempty"`
+	ListLimit        int      `json:"list_limit,omitempty"`
+	Editor           string   `json:"editor,omitempty"`
+	Pager            string   `json:"pager,omitempty"`
+	// MergeTool update で競合したときに使うマージツール（$BASE $LOCAL $REMOTE $MERGED を使える）
+	MergeTool string `json:"merge_tool,omitempty"`
+
+	// FrontMatterFormat 新しく作るファイルのFront Matterの形式（yaml, toml, json）
+	FrontMatterFormat string `json:"front_matter_format,omitempty"`
+	// Tree カテゴリをディレクトリにして記事ファイルを保存する（開発/API/123-タイトル.md）
+	Tree *bool `json:"tree,omitempty"`
+	// OnRemoteDelete sync でリモートの記事が削除されていたときの扱い（trash, keep）
+	OnRemoteDelete string `json:"on_remote_delete,omitempty"`
+	// OnLocalDelete sync で同期済みのファイルが削除されていたときの扱い（ignore, prompt, archive, delete）
+	OnLocalDelete string `json:"on_local_delete,omitempty"`
+}
+
+// リモートの記事が削除されていたときの扱い
+const (
+	// RemoteDeleteTrash ローカルファイルを .trash に移動する（デフォルト）
+	RemoteDeleteTrash = "trash"
+	// RemoteDeleteKeep ローカルファイルをそのまま残す
+	RemoteDeleteKeep = "keep"
+)
+
+// 同期済みのファイルが削除されていたときの扱い
+const (
+	// LocalDeleteIgnore 何もしない（デフォルト）
+	LocalDeleteIgnore = "ignore"
+	// LocalDeletePrompt 記事ごとに確認する
+	LocalDeletePrompt = "prompt"
+	// LocalDeleteArchive 記事を Archived/ カテゴリに移動する
+	LocalDeleteArchive = "archive"
+	// LocalDeleteDelete 記事を削除する
+	LocalDeleteDelete = "delete"
+)
+
+// GetDefaults デフォルト値を返す（未設定ならゼロ値）
+// プロジェクト設定がある場合はその値で上書きする
+func (c *Config) GetDefaults() Defaults {
+	var defaults Defaults
+	if c != nil && c.Defaults != nil {
+		defaults = *c.Defaults
+	}
+	if c != nil {
+		c.project.apply(&defaults)
+	}
+	return defaults
+}
+
+// Project 読み込んだプロジェクト設定（無ければ nil）
+func (c *Config) Project() *ProjectConfig {
+	return c.project
+}
+
+// CommitMessage message_template から更新メッセージを生成（未設定なら空文字）
+// 使用できるプレースホルダー: {title} {number} {file} {date}
+func (d Defaults) CommitMessage(title string, number int, file string, now time.Time) string {
+	if d.MessageTemplate == "" {
+		return ""
+	}
+	r := strings.NewReplacer(
+		"{title}", title,
+		"{number}", strconv.Itoa(number),
+		"{file}", file,
+		"{date}", now.Format("2006-01-02"),
+	)
+	return r.Replace(d.MessageTemplate)
+}
+
+// TreeLayout カテゴリをディレクトリにして保存するか
+func (d Defaults) TreeLayout() bool {
+	return d.Tree != nil && *d.Tree
+}
+
+// RemoteDeletePolicy リモートの記事が削除されていたときの扱い（未設定なら trash）
+func (d Defaults) RemoteDeletePolicy() string {
+	if d.OnRemoteDelete == "" {
+		return RemoteDeleteTrash
+	}
+	return d.OnRemoteDelete
+}
+
+// LocalDeletePolicy 同期済みのファイルが削除されていたときの扱い（未設定なら ignore）
+func (d Defaults) LocalDeletePolicy() string {
+	if d.OnLocalDelete == "" {
+		return LocalDeleteIgnore
+	}
+	return d.OnLocalDelete
+}
+
+// FileName 記事を保存するファイル名（ワークスペースや出力先からの相対パス）
+// tree のときは、テンプレートに {category} が無ければカテゴリのディレクトリに置く
+func (d Defaults) FileName(number int, name, category string) string {
+	tmpl := d.FilenameTemplate
+	if tmpl == "" {
+		tmpl = naming.DefaultTemplate
+	}
+	if d.TreeLayout() && !strings.Contains(tmpl, "{category}") {
+		tmpl = "{category}/" + tmpl
+	}
+	return naming.FileName(tmpl, number, name, category)
+}
+
+// LoadDefaults 設定ファイルからデフォルト値を読み込む
+// 設定ファイルが無い場合もエラーにせずゼロ値を返す
+func LoadDefaults() Defaults {
+	config, err := loadFile()
+	if err != nil {
+		config = &Config{}
+	}
+	config.project, _ = LoadProjectConfig()
+	return config.GetDefaults()
+}
+
+// defaultKey config get/set/unset で扱うキー
+type defaultKey struct {
+	get   func(d *Defaults) string
+	set   func(d *Defaults, value string) error
+	unset func(d *Defaults)
+}
+
+var defaultKeys = map[string]defaultKey{
+	"category": {
+		get:   func(d *Defaults) string { return d.Category },
+		set:   func(d *Defaults, v string) error { d.Category = v; return nil },
+		unset: func(d *Defaults) { d.Category = "" },
+	},
+	"tags": {
+		get: func(d *Defaults) string { return strings.Join(d.Tags, ",") },
+		set: func(d *Defaults, v string) error {
+			d.Tags = nil
+			for _, tag := range strings.Split(v, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					d.Tags = append(d.Tags, tag)
+				}
+			}
+			return nil
+		},
+		unset: func(d *Defaults) { d.Tags = nil },
+	},
+	"message_template": {
+		get:   func(d *Defaults) string { return d.MessageTemplate },
+		set:   func(d *Defaults, v string) error { d.MessageTemplate = v; return nil },
+		unset: func(d *Defaults) { d.MessageTemplate = "" },
+	},
+	"output_dir": {
+		get:   func(d *Defaults) string { return d.OutputDir },
+		set:   func(d *Defaults, v string) error { d.OutputDir = v; return nil },
+		unset: func(d *Defaults) { d.OutputDir = "" },
+	},
+	"filename_template": {
+		get: func(d *Defaults) string { return d.FilenameTemplate },
+		set: func(d *Defaults, v string) error {
+			if !strings.HasSuffix(v, ".md") {
+				return fmt.Errorf("filename_template は .md で終わるようにしてください: %s", v)
+			}
+			// 記事ごとに異なる部分が無いと、すべての記事が同じファイル名になる
+			if !strings.Contains(v, "{number}") && !strings.Contains(v, "{name}") && !strings.Contains(v, "{slug}") {
+				return fmt.Errorf("filename_template には {number} {name} {slug} のいずれかを含めてください: %s", v)
+			}
+			d.FilenameTemplate = v
+			return nil
+		},
+		unset: func(d *Defaults) { d.FilenameTemplate = "" },
+	},
+	"wip": {
+		get: func(d *Defaults) string {
+			if d.Wip == nil {
+				return ""
+			}
+			return strconv.FormatBool(*d.Wip)
+		},
+		set: func(d *Defaults, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("wip には true か false を指定してください: %s", v)
+			}
+			d.Wip = &b
+			return nil
+		},
+		unset: func(d *Defaults) { d.Wip = nil },
+	},
+	"list_limit": {
+		get: func(d *Defaults) string {
+			if d.ListLimit == 0 {
+				return ""
+			}
+			return strconv.Itoa(d.ListLimit)
+		},
+		set: func(d *Defaults, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("list_limit には正の整数を指定してください: %s", v)
+			}
+			d.ListLimit = n
+			return nil
+		},
+		unset: func(d *Defaults) { d.ListLimit = 0 },
+	},
+	"editor": {
+		get:   func(d *Defaults) string { return d.Editor },
+		set:   func(d *Defaults, v string) error { d.Editor = v; return nil },
+		unset: func(d *Defaults) { d.Editor = "" },
+	},
+	"pager": {
+		get:   func(d *Defaults) string { return d.Pager },
+		set:   func(d *Defaults, v string) error { d.Pager = v; return nil },
+		unset: func(d *Defaults) { d.Pager = "" },
+	},
+	"merge_tool": {
+		get:   func(d *Defaults) string { return d.MergeTool },
+		set:   func(d *Defaults, v string) error { d.MergeTool = v; return nil },
+		unset: func(d *Defaults) { d.MergeTool = "" },
+	},
+	"front_matter_format": {
+		get: func(d *Defaults) string { return d.FrontMatterFormat },
+		set: func(d *Defaults, v string) error {
+			switch v = strings.ToLower(v); v {
+			case "yaml", "toml", "json":
+				d.FrontMatterFormat = v
+				return nil
+			}
+			return fmt.Errorf("front_matter_format には yaml, toml, json のいずれかを指定してください: %s", v)
+		},
+		unset: func(d *Defaults) { d.FrontMatterFormat = "" },
+	},
+	"tree": {
+		get: func(d *Defaults) string {
+			if d.Tree == nil {
+				return ""
+			}
+			return strconv.FormatBool(*d.Tree)
+		},
+		set: func(d *Defaults, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("tree には true か false を指定してください: %s", v)
+			}
+			d.Tree = &b
+			return nil
+		},
+		unset: func(d *Defaults) { d.Tree = nil },
+	},
+	"on_remote_delete": {
+		get: func(d *Defaults) string { return d.OnRemoteDelete },
+		set: func(d *Defaults, v string) error {
+			switch v = strings.ToLower(v); v {
+			case RemoteDeleteTrash, RemoteDeleteKeep:
+				d.OnRemoteDelete = v
+				return nil
+			}
+			return fmt.Errorf("on_remote_delete には trash, keep のいずれかを指定してください: %s", v)
+		},
+		unset: func(d *Defaults) { d.OnRemoteDelete = "" },
+	},
+	"on_local_delete": {
+		get: func(d *Defaults) string { return d.OnLocalDelete },
+		set: func(d *Defaults, v string) error {
+			switch v = strings.ToLower(v); v {
+			case LocalDeleteIgnore, LocalDeletePrompt, LocalDeleteArchive, LocalDeleteDelete:
+				d.OnLocalDelete = v
+				return nil
+			}
+			return fmt.Errorf("on_local_delete には ignore, prompt, archive, delete のいずれかを指定してください: %s", v)
+		},
+		unset: func(d *Defaults) { d.OnLocalDelete = "" },
+	},
+}
+
+// DefaultKeys 設定できるキーの一覧を返す
+func DefaultKeys() []string {
+	keys := make([]string, 0, len(defaultKeys))
+	for key := range defaultKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lookupDefaultKey(key string) (defaultKey, error) {
+	k, ok := defaultKeys[key]
+	if !ok {
+		return defaultKey{}, fmt.Errorf("不明なキーです: %s (使用できるキー: %s)", key, strings.Join(DefaultKeys(), ", "))
+	}
+	return k, nil
+}
+
+// GetDefault デフォルト値を取得（未設定なら空文字）
+func GetDefault(key string) (string, error) {
+	k, err := lookupDefaultKey(key)
+	if err != nil {
+		return "", err
+	}
+	defaults := LoadDefaults()
+	return k.get(&defaults), nil
+}
+
+// ListDefaults 設定済みのデフォルト値をキー順に返す
+func ListDefaults() [][2]string {
+	defaults := LoadDefaults()
+	var values [][2]string
+	for _, key := range DefaultKeys() {
+		if v := defaultKeys[key].get(&defaults); v != "" {
+			values = append(values, [2]string{key, v})
+		}
+	}
+	return values
+}
+
+// SetDefault デフォルト値を設定して保存
+func SetDefault(key, value string) error {
+	k, err := lookupDefaultKey(key)
+	if err != nil {
+		return err
+	}
+	return updateDefaults(func(d *Defaults) error { return k.set(d, value) })
+}
+
+// UnsetDefault デフォルト値を削除して保存
+func UnsetDefault(key string) error {
+	k, err := lookupDefaultKey(key)
+	if err != nil {
+		return err
+	}
+	return updateDefaults(func(d *Defaults) error { k.unset(d); return nil })
+}
+
+func updateDefaults(fn func(d *Defaults) error) error {
+	config, err := loadFile()
+	if err != nil {
+		// 設定ファイルがまだ無い場合は新規作成
+		config = &Config{}
+	}
+	if config.Defaults == nil {
+		config.Defaults = &Defaults{}
+	}
+	if err := fn(config.Defaults); err != nil {
+		return err
+	}
+	if config.Defaults.empty() {
+		config.Defaults = nil
+	}
+	return Save(config)
+}
+
+// empty すべてのキーが未設定か
+func (d *Defaults) empty() bool {
+	for _, k := range defaultKeys {
+		if k.get(d) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/testutil"
+)
+
+func TestSetDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "正常系：カテゴリを設定できる",
+			key:   "category",
+			value: "開発/メモ",
+			want:  "開発/メモ",
+		},
+		{
+			name:  "正常系：タグはカンマ区切りで設定できる",
+			key:   "tags",
+			value: "go, cli,",
+			want:  "go,cli",
+		},
+		{
+			name:  "正常系：WIPを真偽値で設定できる",
+			key:   "wip",
+			value: "true",
+			want:  "true",
+		},
+		{
+			name:    "異常系：WIPに真偽値以外を指定",
+			key:     "wip",
+			value:   "maybe",
+			wantErr: true,
+		},
+		{
+			name:    "異常系：一覧件数に0を指定",
+			key:     "list_limit",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:  "正常系：Front Matterの形式を設定できる",
+			key:   "front_matter_format",
+			value: "TOML",
+			want:  "toml",
+		},
+		{
+			name:    "異常系：Front Matterに未対応の形式を指定",
+			key:     "front_matter_format",
+			value:   "xml",
+			wantErr: true,
+		},
+		{
+			name:  "正常系：ファイル名テンプレートを設定できる",
+			key:   "filename_template",
+			value: "{category}/{slug}.md",
+			want:  "{category}/{slug}.md",
+		},
+		{
+			name:    "異常系：ファイル名テンプレートに記事ごとに異なる部分が無い",
+			key:     "filename_template",
+			value:   "{category}/index.md",
+			wantErr: true,
+		},
+		{
+			name:    "異常系：ファイル名テンプレートが .md で終わらない",
+			key:     "filename_template",
+			value:   "{number}-{name}.txt",
+			wantErr: true,
+		},
+		{
+			name:  "正常系：カテゴリをディレクトリにする配置を設定できる",
+			key:   "tree",
+			value: "true",
+			want:  "true",
+		},
+		{
+			name:  "正常系：リモートで削除された記事の扱いを設定できる",
+			key:   "on_remote_delete",
+			value: "keep",
+			want:  "keep",
+		},
+		{
+			name:  "正常系：ローカルで削除されたファイルの扱いを設定できる",
+			key:   "on_local_delete",
+			value: "Archive",
+			want:  "archive",
+		},
+		{
+			name:    "異常系：ローカルで削除されたファイルの扱いに未対応の値を指定",
+			key:     "on_local_delete",
+			value:   "remove",
+			wantErr: true,
+		},
+		{
+			name:    "異常系：不明なキー",
+			key:     "unknown",
+			value:   "x",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: 空の設定
+			tmpDir := testutil.CreateTempDir(t)
+			ConfigFile = filepath.Join(tmpDir, "config.json")
+
+			// When
+			err := SetDefault(tt.key, tt.value)
+
+			// Then
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := GetDefault(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetDefault() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnsetDefault(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = testutil.CreateTestConfigFile(t, tmpDir)
+
+	if err := SetDefault("list_limit", "30"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetDefault("pager", "less -R"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ListDefaults(); len(got) != 2 {
+		t.Errorf("ListDefaults() = %v, want 2 entries", got)
+	}
+
+	if err := UnsetDefault("list_limit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnsetDefault("pager"); err != nil {
+		t.Fatal(err)
+	}
+
+	// すべて削除するとdefaultsごと消え、接続情報は残る
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Defaults != nil {
+		t.Errorf("Defaults = %+v, want nil", cfg.Defaults)
+	}
+	if cfg.TeamName != "test-team" {
+		t.Errorf("TeamName = %v, want test-team", cfg.TeamName)
+	}
+}
+
+func TestDefaults_FileName(t *testing.T) {
+	tree := true
+	tests := []struct {
+		name     string
+		defaults Defaults
+		category string
+		want     string
+	}{
+		{
+			name:     "正常系：デフォルトはカテゴリに関係なく同じディレクトリ",
+			category: "開発/インフラ",
+			want:     "123-設計.md",
+		},
+		{
+			name:     "正常系：tree ならカテゴリをディレクトリにする",
+			defaults: Defaults{Tree: &tree},
+			category: "開発/インフラ",
+			want:     "開発/インフラ/123-設計.md",
+		},
+		{
+			name:     "正常系：tree ではファイル名テンプレートも使う",
+			defaults: Defaults{Tree: &tree, FilenameTemplate: "{name}.md"},
+			category: "開発",
+			want:     "開発/設計.md",
+		},
+		{
+			name:     "エッジケース：テンプレートに {category} があればそのまま",
+			defaults: Defaults{Tree: &tree, FilenameTemplate: "docs/{category}/{number}.md"},
+			category: "開発",
+			want:     "docs/開発/123.md",
+		},
+		{
+			name:     "エッジケース：カテゴリの無い記事はルートに置く",
+			defaults: Defaults{Tree: &tree},
+			want:     "123-設計.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.defaults.FileName(123, "設計", tt.category); got != tt.want {
+				t.Errorf("FileName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// 設定値の出どころ
+const (
+	OriginFlag    = "flag"
+	OriginEnv     = "env"
+	OriginProject = "project"
+	OriginUser    = "user"
+
+	OriginCredentialHelper = "credential_helper"
+)
+
+// Origin 設定値がどこから来たかを表す
+type Origin struct {
+	Source string // flag, env, project, user, credential_helper のいずれか
+	Detail string // 設定ファイルのパス、環境変数名、フラグ名など
+}
+
+func (o Origin) String() string {
+	if o.Source == "" {
+		return "未設定"
+	}
+	if o.Detail == "" {
+		return o.Source
+	}
+	return fmt.Sprintf("%s: %s", o.Source, o.Detail)
+}
+
+// Origin 指定したキー（profile, team_name, access_token）の出どころを返す
+func (c *Config) Origin(key string) Origin {
+	return c.origins[key]
+}
+
+// resolve 各レイヤーの設定から有効なチームとトークンを決定する
+func (c *Config) resolve(project *ProjectConfig) error {
+	c.origins = map[string]Origin{}
+	userOrigin := Origin{Source: OriginUser, Detail: c.path}
+
+	// チーム（プロファイル）の選択: フラグ > 環境変数 > プロジェクト設定 > ユーザー設定
+	var selector string
+	var origin Origin
+	switch {
+	case SelectedProfile != "":
+		selector, origin = SelectedProfile, Origin{Source: OriginFlag, Detail: "--profile"}
+	case os.Getenv(ProfileEnv) != "":
+		selector, origin = os.Getenv(ProfileEnv), Origin{Source: OriginEnv, Detail: ProfileEnv}
+	case os.Getenv(TeamEnv) != "":
+		selector, origin = os.Getenv(TeamEnv), Origin{Source: OriginEnv, Detail: TeamEnv}
+	case project != nil && project.selector() != "":
+		selector, origin = project.selector(), Origin{Source: OriginProject, Detail: project.Path}
+	case c.DefaultProfile != "":
+		selector, origin = c.DefaultProfile, userOrigin
+	case len(c.Profiles) == 1:
+		selector, origin = c.ProfileNames()[0], userOrigin
+	}
+
+	// フラグで選んだプロファイルにトークンがあれば、トークンの環境変数より優先する（フラグ > 環境変数 > 設定ファイル）
+	flagProfileToken := false
+	if selector != "" {
+		if name, profile := c.findProfile(selector); profile != nil {
+			flagProfileToken = origin.Source == OriginFlag && (profile.AccessToken != "" || c.credentialHelper(name) != nil)
+			c.ActiveProfile = name
+			c.TeamName = profile.TeamName
+			c.AccessToken = profile.AccessToken
+			c.origins["profile"] = origin
+			c.origins["team_name"] = userOrigin
+			c.origins["access_token"] = userOrigin
+		} else if origin.Detail != ProfileEnv && os.Getenv(TokenEnv) != "" {
+			// プロファイルが無くてもトークンが環境変数で与えられていればチーム名として扱う
+			c.TeamName = selector
+			c.origins["team_name"] = origin
+		} else {
+			return fmt.Errorf("プロファイルが見つかりません: %s", selector)
+		}
+	}
+
+	if token := os.Getenv(TokenEnv); token != "" && !flagProfileToken {
+		c.AccessToken = token
+		c.origins["access_token"] = Origin{Source: OriginEnv, Detail: TokenEnv}
+		return nil
+	}
+
+	// クレデンシャルヘルパーがあればそちらを優先し、失敗時は平文のトークンを使う
+	if helper := c.credentialHelper(c.ActiveProfile); helper != nil && c.TeamName != "" {
+		token, err := helper.Get(c.TeamName)
+		switch {
+		case err == nil && token != "":
+			c.AccessToken = token
+			c.origins["access_token"] = Origin{Source: OriginCredentialHelper, Detail: helper.Command}
+		case c.AccessToken == "":
+			if err == nil {
+				err = fmt.Errorf("トークンが返されませんでした")
+			}
+			return fmt.Errorf("クレデンシャルヘルパーからトークンを取得できませんでした: %v", err)
+		}
+	}
+
+	return nil
+}
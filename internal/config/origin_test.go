@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/testutil"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+
+	data := `{
+  "default_profile": "work",
+  "profiles": {
+    "work": {"access_token": "work-token", "team_name": "work-team"},
+    "oss": {"access_token": "oss-token", "team_name": "oss-team"},
+    "docs": {"access_token": "docs-token", "team_name": "docs-team"}
+  }
+}`
+	if err := os.WriteFile(ConfigFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// プロジェクト設定を置いたディレクトリに移動
+	projectDir := filepath.Join(tmpDir, "project", "sub")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "project", ProjectFileName), []byte("profile: docs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origDir)
+
+	tests := []struct {
+		name       string
+		flag       string
+		envTeam    string
+		envToken   string
+		wantTeam   string
+		wantToken  string
+		wantSource string
+	}{
+		{
+			name:       "正常系：プロジェクト設定がユーザー設定より優先される",
+			wantTeam:   "docs-team",
+			wantToken:  "docs-token",
+			wantSource: OriginProject,
+		},
+		{
+			name:       "正常系：環境変数がプロジェクト設定より優先される",
+			envTeam:    "oss-team",
+			wantTeam:   "oss-team",
+			wantToken:  "oss-token",
+			wantSource: OriginEnv,
+		},
+		{
+			name:       "正常系：フラグが環境変数より優先される",
+			flag:       "work",
+			envTeam:    "oss-team",
+			wantTeam:   "work-team",
+			wantToken:  "work-token",
+			wantSource: OriginFlag,
+		},
+		{
+			name:       "正常系：トークンの環境変数はプロファイルのトークンを上書きする",
+			envToken:   "env-token",
+			wantTeam:   "docs-team",
+			wantToken:  "env-token",
+			wantSource: OriginProject,
+		},
+		{
+			name:       "正常系：フラグで選んだプロファイルのトークンはトークンの環境変数より優先される",
+			flag:       "work",
+			envToken:   "env-token",
+			wantTeam:   "work-team",
+			wantToken:  "work-token",
+			wantSource: OriginFlag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SelectedProfile = tt.flag
+			defer func() { SelectedProfile = "" }()
+			t.Setenv(TeamEnv, tt.envTeam)
+			t.Setenv(TokenEnv, tt.envToken)
+
+			got, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got.TeamName != tt.wantTeam || got.AccessToken != tt.wantToken {
+				t.Errorf("Load() = (%v, %v), want (%v, %v)", got.TeamName, got.AccessToken, tt.wantTeam, tt.wantToken)
+			}
+			if src := got.Origin("profile").Source; src != tt.wantSource {
+				t.Errorf("Origin(profile) = %v, want %v", src, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestLoad_FlagProfileWithoutToken(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+	data := `{"profiles": {"ci": {"team_name": "ci-team"}, "work": {"access_token": "work-token", "team_name": "work-team"}}}`
+	if err := os.WriteFile(ConfigFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	SelectedProfile = "ci"
+	defer func() { SelectedProfile = "" }()
+	t.Setenv(TokenEnv, "env-token")
+
+	// フラグで選んだプロファイルにトークンが無ければ環境変数のトークンを使う
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.TeamName != "ci-team" || got.AccessToken != "env-token" {
+		t.Errorf("Load() = (%v, %v), want (ci-team, env-token)", got.TeamName, got.AccessToken)
+	}
+	if src := got.Origin("access_token").Source; src != OriginEnv {
+		t.Errorf("Origin(access_token) = %v, want %v", src, OriginEnv)
+	}
+}
+
+func TestLoad_EnvOnly(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "missing.json")
+	t.Setenv(TeamEnv, "ci-team")
+	t.Setenv(TokenEnv, "ci-token")
+
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.TeamName != "ci-team" || got.AccessToken != "ci-token" {
+		t.Errorf("Load() = %+v, want ci-team/ci-token", got)
+	}
+	if got.Origin("team_name").Source != OriginEnv || got.Origin("access_token").Source != OriginEnv {
+		t.Errorf("origins = %v, %v, want env", got.Origin("team_name"), got.Origin("access_token"))
+	}
+}
+
+func TestGetConfigPath_XDG(t *testing.T) {
+	ConfigFile = ""
+	home := testutil.CreateTempDir(t)
+	xdg := filepath.Join(home, "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	xdgPath := filepath.Join(xdg, "esa-cli", "config.json")
+	legacyPath := filepath.Join(home, ".esa-cli-config.json")
+
+	// 何も無い場合はXDGのパス
+	if got := getConfigPath(); got != xdgPath {
+		t.Errorf("getConfigPath() = %v, want %v", got, xdgPath)
+	}
+
+	// 従来のファイルだけがある場合はそちらを使う
+	if err := os.WriteFile(legacyPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := getConfigPath(); got != legacyPath {
+		t.Errorf("getConfigPath() = %v, want %v", got, legacyPath)
+	}
+
+	// XDGのファイルがあればそちらを優先
+	if err := os.MkdirAll(filepath.Dir(xdgPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(xdgPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := getConfigPath(); got != xdgPath {
+		t.Errorf("getConfigPath() = %v, want %v", got, xdgPath)
+	}
+}
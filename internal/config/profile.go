@@ -0,0 +1,91 @@
+package config
+
+import "fmt"
+
+// ListProfiles 設定ファイルに登録されたプロファイルを返す
+func ListProfiles() (*Config, error) {
+	return loadFile()
+}
+
+// AddProfile プロファイルを追加（同名の場合は上書き）する
+// クレデンシャルヘルパーが使える場合、トークンは設定ファイルではなくヘルパーに保存する
+func AddProfile(name, teamName, accessToken, credentialHelper string) error {
+	if name == "" {
+		return fmt.Errorf("プロファイル名が指定されていません")
+	}
+	if teamName == "" {
+		return fmt.Errorf("チーム名が指定されていません")
+	}
+
+	config, err := loadFile()
+	if err != nil {
+		// 設定ファイルがまだ無い場合は新規作成
+		config = &Config{}
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]*Profile{}
+	}
+	profile := &Profile{AccessToken: accessToken, TeamName: teamName, CredentialHelper: credentialHelper}
+	config.Profiles[name] = profile
+
+	helper := config.credentialHelper(name)
+	if accessToken == "" && helper == nil {
+		return fmt.Errorf("アクセストークンが指定されていません")
+	}
+	if helper != nil && accessToken != "" && !helper.readOnly() {
+		if err := helper.Store(teamName, accessToken); err != nil {
+			return fmt.Errorf("クレデンシャルヘルパーへの保存に失敗しました: %v", err)
+		}
+		profile.AccessToken = ""
+	}
+	if config.DefaultProfile == "" {
+		config.DefaultProfile = name
+	}
+
+	return Save(config)
+}
+
+// RemoveProfile プロファイルを削除する
+func RemoveProfile(name string) error {
+	config, err := loadFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Profiles[name]; !ok {
+		return fmt.Errorf("プロファイルが見つかりません: %s", name)
+	}
+
+	// ヘルパーに保存したトークンも削除する（読み取り専用のヘルパーは対象外）
+	// 削除できなければトークンが残らないようプロファイルも削除しない
+	if helper := config.credentialHelper(name); helper != nil && !helper.readOnly() {
+		if err := helper.Erase(config.Profiles[name].TeamName); err != nil {
+			return fmt.Errorf("クレデンシャルヘルパーのトークンを削除できませんでした: %v", err)
+		}
+	}
+
+	delete(config.Profiles, name)
+	if config.DefaultProfile == name {
+		config.DefaultProfile = ""
+		// 残りが1つだけならそれをデフォルトにする
+		if names := config.ProfileNames(); len(names) == 1 {
+			config.DefaultProfile = names[0]
+		}
+	}
+
+	return Save(config)
+}
+
+// UseProfile デフォルトのプロファイルを切り替える
+func UseProfile(name string) error {
+	config, err := loadFile()
+	if err != nil {
+		return err
+	}
+	profileName, profile := config.findProfile(name)
+	if profile == nil {
+		return fmt.Errorf("プロファイルが見つかりません: %s", name)
+	}
+
+	config.DefaultProfile = profileName
+	return Save(config)
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/testutil"
+)
+
+func TestLoad_Profiles(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+
+	data := `{
+  "default_profile": "work",
+  "profiles": {
+    "work": {"access_token": "work-token", "team_name": "work-team"},
+    "oss": {"access_token": "oss-token", "team_name": "oss-team"}
+  }
+}`
+	if err := os.WriteFile(ConfigFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		selected string
+		env      string
+		wantTeam string
+		wantErr  bool
+	}{
+		{
+			name:     "正常系：デフォルトのプロファイルが選択される",
+			wantTeam: "work-team",
+		},
+		{
+			name:     "正常系：フラグでプロファイルを指定できる",
+			selected: "oss",
+			wantTeam: "oss-team",
+		},
+		{
+			name:     "正常系：チーム名でプロファイルを指定できる",
+			selected: "oss-team",
+			wantTeam: "oss-team",
+		},
+		{
+			name:     "正常系：環境変数でプロファイルを指定できる",
+			env:      "oss",
+			wantTeam: "oss-team",
+		},
+		{
+			name:     "正常系：フラグは環境変数より優先される",
+			selected: "work",
+			env:      "oss",
+			wantTeam: "work-team",
+		},
+		{
+			name:     "異常系：存在しないプロファイル",
+			selected: "unknown",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SelectedProfile = tt.selected
+			defer func() { SelectedProfile = "" }()
+			t.Setenv(ProfileEnv, tt.env)
+
+			got, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.TeamName != tt.wantTeam {
+				t.Errorf("Load() TeamName = %v, want %v", got.TeamName, tt.wantTeam)
+			}
+		})
+	}
+}
+
+func TestLoad_MigrateLegacyConfig(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = testutil.CreateTestConfigFile(t, tmpDir)
+
+	got, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ActiveProfile != "test-team" || got.AccessToken != "test-token" {
+		t.Errorf("Load() = %+v, want profile test-team", got)
+	}
+
+	// 移行後の設定ファイルがプロファイル形式で保存されていることを確認
+	data, err := os.ReadFile(ConfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]interface{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := saved["team_name"]; ok {
+		t.Errorf("legacy team_name should be removed: %s", data)
+	}
+	if saved["default_profile"] != "test-team" {
+		t.Errorf("default_profile = %v, want test-team", saved["default_profile"])
+	}
+}
+
+func TestProfileCommands(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+
+	// Given: 2つのプロファイルを追加
+	if err := AddProfile("work", "work-team", "work-token", ""); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+	if err := AddProfile("oss", "oss-team", "oss-token", ""); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+
+	cfg, err := ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.ProfileNames(); len(got) != 2 || got[0] != "oss" || got[1] != "work" {
+		t.Errorf("ProfileNames() = %v, want [oss work]", got)
+	}
+	if cfg.DefaultProfile != "work" {
+		t.Errorf("DefaultProfile = %v, want work", cfg.DefaultProfile)
+	}
+
+	// When: デフォルトを切り替え
+	if err := UseProfile("oss"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.TeamName != "oss-team" {
+		t.Errorf("Load() TeamName = %v, want oss-team", loaded.TeamName)
+	}
+
+	// When: デフォルトのプロファイルを削除
+	if err := RemoveProfile("oss"); err != nil {
+		t.Fatalf("RemoveProfile() error = %v", err)
+	}
+	cfg, err = ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultProfile != "work" {
+		t.Errorf("DefaultProfile = %v, want work", cfg.DefaultProfile)
+	}
+
+	if err := RemoveProfile("oss"); err == nil {
+		t.Error("RemoveProfile() error = nil, want error")
+	}
+	if err := UseProfile("unknown"); err == nil {
+		t.Error("UseProfile() error = nil, want error")
+	}
+}
+
+func TestRemoveProfile_EraseError(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+	ConfigFile = filepath.Join(tmpDir, "config.json")
+	data := `{"profiles": {"work": {"team_name": "work-team", "credential_helper": "false"}}}`
+	if err := os.WriteFile(ConfigFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// ヘルパーのトークンを削除できなければエラーにし、プロファイルも残す
+	if err := RemoveProfile("work"); err == nil {
+		t.Fatal("RemoveProfile() error = nil, want error")
+	}
+	cfg, err := ListProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Profiles["work"]; !ok {
+		t.Error("プロファイルが削除されました")
+	}
+}
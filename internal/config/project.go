@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName プロジェクト設定ファイルの名前
+const ProjectFileName = ".esa-cli.yml"
+
+// ProjectConfig ディレクトリに紐づくプロジェクト設定
+type ProjectConfig struct {
+	Profile string `yaml:"profile,omitempty"`
+	Team    string `yaml:"team,omitempty"`
+
+	// Category このディレクトリ配下の記事のルートカテゴリ
+	Category string `yaml:"category,omitempty"`
+	// FilenameTemplate 保存するファイル名のテンプレート（ユーザー設定より優先）
+	FilenameTemplate string `yaml:"filename_template,omitempty"`
+	// FrontMatterFormat 新しく作るファイルのFront Matterの形式（ユーザー設定より優先）
+	FrontMatterFormat string `yaml:"front_matter_format,omitempty"`
+	// Tree カテゴリをディレクトリにして保存する（ユーザー設定より優先）
+	Tree *bool `yaml:"tree,omitempty"`
+	// OnRemoteDelete リモートの記事が削除されていたときの扱い（ユーザー設定より優先）
+	OnRemoteDelete string `yaml:"on_remote_delete,omitempty"`
+	// OnLocalDelete 同期済みのファイルが削除されていたときの扱い（ユーザー設定より優先）
+	OnLocalDelete string `yaml:"on_local_delete,omitempty"`
+	// Ignore update-all などで対象外にするファイルのパターン（ルートからの相対パス）
+	Ignore []string `yaml:"ignore,omitempty"`
+
+	// Path 読み込んだ設定ファイルのパス
+	Path string `yaml:"-"`
+}
+
+// selector プロファイル選択に使う値を返す
+func (p *ProjectConfig) selector() string {
+	if p.Profile != "" {
+		return p.Profile
+	}
+	return p.Team
+}
+
+// apply プロジェクト設定でデフォルト値を上書きする
+func (p *ProjectConfig) apply(d *Defaults) {
+	if p == nil {
+		return
+	}
+	if p.FilenameTemplate != "" {
+		d.FilenameTemplate = p.FilenameTemplate
+	}
+	if p.FrontMatterFormat != "" {
+		d.FrontMatterFormat = p.FrontMatterFormat
+	}
+	if p.Tree != nil {
+		d.Tree = p.Tree
+	}
+	if p.OnRemoteDelete != "" {
+		d.OnRemoteDelete = p.OnRemoteDelete
+	}
+	if p.OnLocalDelete != "" {
+		d.OnLocalDelete = p.OnLocalDelete
+	}
+	if p.Category != "" {
+		// ルートカテゴリはユーザー設定のカテゴリより優先する
+		d.Category = p.Category
+	}
+}
+
+// Root プロジェクトのルートディレクトリ
+func (p *ProjectConfig) Root() string {
+	return filepath.Dir(p.Path)
+}
+
+// CategoryFor ルートカテゴリを考慮したカテゴリを返す
+// 空ならルートカテゴリ、ルートカテゴリ配下でなければ先頭にルートカテゴリを付ける
+func (p *ProjectConfig) CategoryFor(category string) string {
+	if p == nil || p.Category == "" {
+		return category
+	}
+	root := strings.Trim(p.Category, "/")
+	category = strings.Trim(category, "/")
+	if category == "" {
+		return root
+	}
+	if category == root || strings.HasPrefix(category, root+"/") {
+		return category
+	}
+	return root + "/" + category
+}
+
+// Ignored パスが ignore のパターンに一致するか
+// パターンはルートからの相対パス、ファイル名、またはディレクトリ（末尾 /）に対して照合する
+func (p *ProjectConfig) Ignored(path string) bool {
+	if p == nil || len(p.Ignore) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(p.Root(), abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range p.Ignore {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(rel, pattern) || strings.Contains(rel, "/"+pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FindProjectConfig dirから親ディレクトリへ遡ってプロジェクト設定を探す
+// 見つからない場合は nil を返す
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return readProjectConfig(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func readProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var project ProjectConfig
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("プロジェクト設定の解析に失敗しました: %s: %v", path, err)
+	}
+	project.Path = path
+
+	return &project, nil
+}
+
+// LoadProjectConfig カレントディレクトリからプロジェクト設定を探す
+// 見つからない場合は nil を返す
+func LoadProjectConfig() (*ProjectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, nil
+	}
+	return FindProjectConfig(wd)
+}
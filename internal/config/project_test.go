@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	content := "team: docs\ncategory: 開発/esa-cli\nfilename_template: \"{name}.md\"\nignore:\n  - drafts/\n  - \"*.tmp.md\"\n"
+	if err := os.WriteFile(filepath.Join(root, ProjectFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := FindProjectConfig(sub)
+	if err != nil {
+		t.Fatalf("FindProjectConfig() error = %v", err)
+	}
+	if project == nil {
+		t.Fatal("プロジェクト設定が見つかりません")
+	}
+	if project.Team != "docs" || project.Category != "開発/esa-cli" || project.FilenameTemplate != "{name}.md" {
+		t.Errorf("読み込んだ設定が不正です: %+v", project)
+	}
+	if project.Root() != root {
+		t.Errorf("Root() = %q, want %q", project.Root(), root)
+	}
+
+	if project, err := FindProjectConfig(t.TempDir()); err != nil || project != nil {
+		t.Errorf("設定が無い場合は nil を返す必要があります: %+v, %v", project, err)
+	}
+}
+
+func TestProjectConfig_CategoryFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  *ProjectConfig
+		category string
+		want     string
+	}{
+		{
+			name:     "正常系：未指定ならルートカテゴリ",
+			project:  &ProjectConfig{Category: "開発/esa-cli"},
+			category: "",
+			want:     "開発/esa-cli",
+		},
+		{
+			name:     "正常系：相対カテゴリにルートカテゴリを付ける",
+			project:  &ProjectConfig{Category: "開発/esa-cli"},
+			category: "設計",
+			want:     "開発/esa-cli/設計",
+		},
+		{
+			name:     "正常系：ルートカテゴリ配下はそのまま",
+			project:  &ProjectConfig{Category: "開発/esa-cli"},
+			category: "開発/esa-cli/設計",
+			want:     "開発/esa-cli/設計",
+		},
+		{
+			name:     "エッジケース：前後のスラッシュは取り除く",
+			project:  &ProjectConfig{Category: "/開発/"},
+			category: "/設計/",
+			want:     "開発/設計",
+		},
+		{
+			name:     "エッジケース：プロジェクト設定が無い",
+			project:  nil,
+			category: "設計",
+			want:     "設計",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.project.CategoryFor(tt.category); got != tt.want {
+				t.Errorf("CategoryFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_Ignored(t *testing.T) {
+	root := t.TempDir()
+	project := &ProjectConfig{
+		Path:   filepath.Join(root, ProjectFileName),
+		Ignore: []string{"drafts/", "*.tmp.md", "notes/private.md"},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "正常系：ディレクトリ指定に一致", path: filepath.Join(root, "drafts", "1-a.md"), want: true},
+		{name: "正常系：サブディレクトリ内のディレクトリ指定に一致", path: filepath.Join(root, "x", "drafts", "1-a.md"), want: true},
+		{name: "正常系：ファイル名のパターンに一致", path: filepath.Join(root, "x", "2-b.tmp.md"), want: true},
+		{name: "正常系：相対パスに一致", path: filepath.Join(root, "notes", "private.md"), want: true},
+		{name: "正常系：一致しない", path: filepath.Join(root, "3-c.md"), want: false},
+		{name: "エッジケース：プロジェクト外のファイル", path: filepath.Join(t.TempDir(), "drafts", "1-a.md"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := project.Ignored(tt.path); got != tt.want {
+				t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDefaults_Project(t *testing.T) {
+	config := &Config{
+		Defaults: &Defaults{Category: "メモ", FilenameTemplate: "{number}.md", ListLimit: 5},
+		project:  &ProjectConfig{Category: "開発/esa-cli", FilenameTemplate: "{name}.md"},
+	}
+
+	defaults := config.GetDefaults()
+	if defaults.Category != "開発/esa-cli" {
+		t.Errorf("Category = %q, want %q", defaults.Category, "開発/esa-cli")
+	}
+	if defaults.FilenameTemplate != "{name}.md" {
+		t.Errorf("FilenameTemplate = %q, want %q", defaults.FilenameTemplate, "{name}.md")
+	}
+	if defaults.ListLimit != 5 {
+		t.Errorf("ListLimit = %d, want 5", defaults.ListLimit)
+	}
+	if config.Defaults.Category != "メモ" {
+		t.Error("ユーザー設定のデフォルト値が書き換えられています")
+	}
+}
@@ -0,0 +1,89 @@
+// Package convert ローカルファイルの本文をアップロードする形に変換し、更新後の記事をローカルの形式に戻す
+package convert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/assets"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// Body アップロードする本文
+type Body struct {
+	// Text アップロードする本文（ローカルの画像は元のURLに、ローカルファイルへのリンクは記事リンクに戻したもの）
+	Text string
+
+	number     int
+	links      *workspace.Links
+	store      *assets.Store
+	withAssets bool
+	localLinks bool
+}
+
+// ToUpload ローカルの本文をアップロードする本文に変換する
+// dir は本文を読み込んだファイルのディレクトリ
+// 変換できなかった部分はそのまま残し、diff の出力に混ざらないよう警告は標準エラー出力に書き出す
+func ToUpload(links *workspace.Links, number int, body, dir string) *Body {
+	b := &Body{Text: body, number: number, links: links}
+
+	store, err := assets.OpenWorkspace(http.DefaultClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %s の読み込みに失敗しました: %v\n", assets.ManifestFile, err)
+	}
+	if store != nil && number > 0 {
+		b.store = store
+		b.Text = store.Restore(number, body, dir)
+		b.withAssets = b.Text != body
+	}
+
+	linked, err := links.ToRemote(b.Text, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  リンクの書き換えに失敗しました: %v\n", err)
+	}
+	b.localLinks = linked != b.Text
+	b.Text = linked
+	return b
+}
+
+// PrepareUpload ToUpload と同じだが、競合マーカーが残っていればアップロードしないようエラーを返す
+func PrepareUpload(links *workspace.Links, number int, body, dir string) (*Body, error) {
+	b := ToUpload(links, number, body, dir)
+	if err := diff.CheckConflictMarkers(b.Text); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Local リモートの本文をローカルと同じ形式（画像・リンクの書き換え）に戻す
+// dir は本文を書き込むファイルのディレクトリ（ファイルを移動した場合は移動先）
+func (b *Body) Local(remote, dir string) string {
+	if b.withAssets {
+		remote, _ = b.store.Localize(context.Background(), b.number, remote, dir)
+		if err := b.store.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s の保存に失敗しました: %v\n", assets.ManifestFile, err)
+		}
+	}
+	if b.localLinks {
+		remote, _ = b.links.ToLocal(remote, dir)
+	}
+	return remote
+}
+
+// FrontMatter 記事のFront Matter
+func FrontMatter(post *types.Post, team string) types.FrontMatter {
+	return types.FrontMatter{
+		Title:           post.Name,
+		Category:        post.Category,
+		Tags:            post.Tags,
+		Wip:             post.Wip,
+		Number:          post.Number,
+		Team:            team,
+		RemoteUpdatedAt: post.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,95 @@
+package convert
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/internal/workspace"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func setupWorkspace(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		config.ProjectFileName: "team: docs\n",
+		"1-設計.md":              "---\ntitle: 設計\n---\n\n本文\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(wd) })
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestPrepareUpload(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantText  string
+		wantLocal string
+		wantErr   error
+	}{
+		{
+			name:      "正常系：ローカルファイルへのリンクを記事リンクに戻し、更新後の本文はローカルの形式に戻す",
+			body:      "[設計](1-設計.md)\n",
+			wantText:  "[設計](/posts/1)\n",
+			wantLocal: "[設計](1-設計.md)\n追記\n",
+		},
+		{
+			name:      "正常系：リンクが無ければそのまま",
+			body:      "本文\n",
+			wantText:  "本文\n",
+			wantLocal: "[設計](/posts/1)\n追記\n",
+		},
+		{
+			name:    "異常系：競合マーカーが残っていればエラー",
+			body:    "<<<<<<< ローカル\nA\n=======\nB\n>>>>>>> リモート\n",
+			wantErr: diff.ErrConflictMarkers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := setupWorkspace(t)
+			links := workspace.NewLinks("docs")
+
+			upload, err := PrepareUpload(links, 2, tt.body, root)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PrepareUpload() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if upload.Text != tt.wantText {
+				t.Errorf("PrepareUpload().Text = %q, want %q", upload.Text, tt.wantText)
+			}
+			if got := upload.Local("[設計](/posts/1)\n追記\n", root); got != tt.wantLocal {
+				t.Errorf("Local() = %q, want %q", got, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestFrontMatter(t *testing.T) {
+	updatedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	post := &types.Post{Number: 12, Name: "設計", Category: "開発", Tags: []string{"go"}, Wip: true, UpdatedAt: updatedAt}
+	fm := FrontMatter(post, "docs")
+	if fm.Title != "設計" || fm.Category != "開発" || len(fm.Tags) != 1 || !fm.Wip || fm.Number != 12 || fm.Team != "docs" {
+		t.Errorf("FrontMatter() = %+v", fm)
+	}
+	if fm.RemoteUpdatedAt != "2025-01-02T03:04:05Z" {
+		t.Errorf("FrontMatter().RemoteUpdatedAt = %q", fm.RemoteUpdatedAt)
+	}
+}
@@ -0,0 +1,196 @@
+// Package diff テキストの差分（行単位・単語単位）を計算する
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Op 差分の種類
+type Op int
+
+const (
+	// Equal 両方に共通
+	Equal Op = iota
+	// Delete 変更前にだけある
+	Delete
+	// Insert 変更後にだけある
+	Insert
+)
+
+// Edit 差分の1要素（行単位の差分では1行、単語単位の差分では連続した単語）
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// Lines 行単位の差分を計算する
+// 末尾の改行の有無と改行コード（CRLF/LF）の違いは無視する
+func Lines(a, b string) []Edit {
+	return compute(splitLines(a), splitLines(b))
+}
+
+// Words 単語単位の差分を計算する
+// 空白で区切られない日本語は1文字ずつ比較し、同じ種類の連続した差分はまとめる
+func Words(a, b string) []Edit {
+	var merged []Edit
+	for _, e := range compute(Tokens(a), Tokens(b)) {
+		if n := len(merged); n > 0 && merged[n-1].Op == e.Op {
+			merged[n-1].Text += e.Text
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// Changed 差分があるか
+func Changed(edits []Edit) bool {
+	for _, e := range edits {
+		if e.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Stat 追加・削除された要素（行単位の差分では行）の数
+func Stat(edits []Edit) (added, deleted int) {
+	for _, e := range edits {
+		switch e.Op {
+		case Insert:
+			added++
+		case Delete:
+			deleted++
+		}
+	}
+	return added, deleted
+}
+
+// Tokens 単語単位の差分に使う単位に分割する
+// 英数字・カタカナ・空白はそれぞれ連続した部分を1つに、それ以外（漢字・ひらがな・記号）は1文字ずつに分ける
+func Tokens(s string) []string {
+	var tokens []string
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for _, class := range []func(rune) bool{isWord, isKatakana, unicode.IsSpace} {
+			if class(runes[i]) {
+				for j < len(runes) && class(runes[j]) {
+					j++
+				}
+				break
+			}
+		}
+		tokens = append(tokens, string(runes[i:j]))
+		i = j
+	}
+	return tokens
+}
+
+func isWord(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
+
+func isKatakana(r rune) bool {
+	return unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// compute 共通の先頭・末尾を除いてからMyersのアルゴリズムで差分を計算する
+func compute(a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, s := range a[:prefix] {
+		edits = append(edits, Edit{Equal, s})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, s := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Equal, s})
+	}
+	return edits
+}
+
+// myers 最短の編集手順を求める
+// 各ステップの状態は到達しうる範囲だけを記録するため、メモリは差分の量の2乗に比例する
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		// ステップ d の直前の状態（k は -d-1 から d+1 まで）
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(a, b []string, trace [][]int) []Edit {
+	var edits []Edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Equal, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Insert, b[y-1]})
+			} else {
+				edits = append(edits, Edit{Delete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
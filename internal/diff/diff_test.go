@@ -0,0 +1,212 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// apply 差分から変更前・変更後のテキストを復元する
+func apply(edits []Edit, sep string) (string, string) {
+	var a, b []string
+	for _, e := range edits {
+		if e.Op != Insert {
+			a = append(a, e.Text)
+		}
+		if e.Op != Delete {
+			b = append(b, e.Text)
+		}
+	}
+	return strings.Join(a, sep), strings.Join(b, sep)
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{
+			name: "正常系：変更された行を削除と追加で表す",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: []Edit{{Equal, "a"}, {Delete, "b"}, {Insert, "B"}, {Equal, "c"}},
+		},
+		{
+			name: "正常系：行の追加",
+			a:    "a\nc",
+			b:    "a\nb\nc",
+			want: []Edit{{Equal, "a"}, {Insert, "b"}, {Equal, "c"}},
+		},
+		{
+			name: "エッジケース：改行コードと末尾の改行の違いは無視する",
+			a:    "a\r\nb\r\n",
+			b:    "a\nb",
+			want: []Edit{{Equal, "a"}, {Equal, "b"}},
+		},
+		{
+			name: "エッジケース：空のテキストとの比較",
+			a:    "",
+			b:    "a\n",
+			want: []Edit{{Insert, "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines_Minimal(t *testing.T) {
+	a := "a\nb\nc\na\nb\nb\na"
+	b := "c\nb\na\nb\na\nc"
+	edits := Lines(a, b)
+
+	gotA, gotB := apply(edits, "\n")
+	if gotA != a || gotB != b {
+		t.Fatalf("Lines() の差分から元のテキストを復元できません: %q, %q", gotA, gotB)
+	}
+	changes := 0
+	for _, e := range edits {
+		if e.Op != Equal {
+			changes++
+		}
+	}
+	// Myersの論文の例は編集距離5
+	if changes != 5 {
+		t.Errorf("Lines() の変更数 = %d, want 5", changes)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{
+			name: "正常系：英数字は単語ごと",
+			s:    "hello world_1",
+			want: []string{"hello", " ", "world_1"},
+		},
+		{
+			name: "正常系：日本語は1文字ずつ、カタカナは連続した部分をまとめる",
+			s:    "新しいサーバーを使う",
+			want: []string{"新", "し", "い", "サーバー", "を", "使", "う"},
+		},
+		{
+			name: "エッジケース：空文字列",
+			s:    "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokens(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokens() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("今日は晴れです。", "今日は雨です。")
+	want := []Edit{{Equal, "今日は"}, {Delete, "晴れ"}, {Insert, "雨"}, {Equal, "です。"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words() = %v, want %v", got, want)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		line := string(rune('a' + i - 1))
+		a = append(a, line)
+		switch i {
+		case 2, 4:
+			b = append(b, strings.ToUpper(line))
+		case 18:
+		default:
+			b = append(b, line)
+		}
+	}
+	hunks := Hunks(Lines(strings.Join(a, "\n"), strings.Join(b, "\n")), 3)
+
+	if len(hunks) != 2 {
+		t.Fatalf("len(Hunks()) = %d, want 2", len(hunks))
+	}
+	got := [][4]int{}
+	for _, h := range hunks {
+		got = append(got, [4]int{h.OldStart, h.OldLines, h.NewStart, h.NewLines})
+	}
+	want := [][4]int{{1, 7, 1, 7}, {15, 6, 15, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hunks() = %v, want %v", got, want)
+	}
+}
+
+func TestWriteUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		a, b string
+		want string
+	}{
+		{
+			name: "正常系：unified形式",
+			opts: Options{Context: 1},
+			a:    "一\n二\n三\n",
+			b:    "一\n弐\n三\n",
+			want: "@@ -1,3 +1,3 @@\n 一\n-二\n+弐\n 三\n",
+		},
+		{
+			name: "正常系：単語単位の差分",
+			opts: Options{Context: 0, Words: true},
+			a:    "今日は晴れです。\n",
+			b:    "今日は雨です。\n",
+			want: "@@ -1,1 +1,1 @@\n今日は[-晴れ-]{+雨+}です。\n",
+		},
+		{
+			name: "正常系：色付き",
+			opts: Options{Context: 0, Color: true},
+			a:    "a",
+			b:    "b",
+			want: "\x1b[36m@@ -1,1 +1,1 @@\x1b[0m\n\x1b[31m-a\x1b[0m\n\x1b[32m+b\x1b[0m\n",
+		},
+		{
+			name: "エッジケース：差分が無ければ何も書かない",
+			opts: Options{Context: 3},
+			a:    "a\n",
+			b:    "a\n",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			WriteUnified(&buf, Lines(tt.a, tt.b), tt.opts)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteUnified() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFields(t *testing.T) {
+	var buf strings.Builder
+	WriteFields(&buf, []Field{
+		{Name: "title", Old: "記事", New: "記事"},
+		{Name: "wip", Old: "true", New: "false"},
+	}, Options{})
+
+	want := "-wip: true\n+wip: false\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteFields() = %q, want %q", got, want)
+	}
+}
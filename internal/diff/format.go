@@ -0,0 +1,179 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// Options 差分の表示方法
+type Options struct {
+	// Context 変更の前後に表示する行数
+	Context int
+	// Color ANSIエスケープシーケンスで色を付ける
+	Color bool
+	// Words 変更された行を単語単位の差分で表示する
+	Words bool
+}
+
+// Hunk 前後の行を含めた変更のまとまり
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Edits              []Edit
+}
+
+// Field メタデータ（タイトル・カテゴリなど）の差分
+type Field struct {
+	Name     string
+	Old, New string
+}
+
+// Hunks 行単位の差分を前後 context 行を含むまとまりに分ける
+// 間の共通部分が 2*context 行以下の変更は1つにまとめる
+func Hunks(edits []Edit, context int) []Hunk {
+	oldPos := make([]int, len(edits)+1)
+	newPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if e.Op != Insert {
+			oldPos[i+1]++
+		}
+		if e.Op != Delete {
+			newPos[i+1]++
+		}
+	}
+
+	var hunks []Hunk
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+		last := i
+		for k := i + 1; k < len(edits); k++ {
+			if edits[k].Op == Equal {
+				continue
+			}
+			if k-last-1 > 2*context {
+				break
+			}
+			last = k
+		}
+		start := max(i-context, 0)
+		stop := min(last+context+1, len(edits))
+		hunks = append(hunks, Hunk{
+			OldStart: lineStart(oldPos[start], oldPos[stop]),
+			OldLines: oldPos[stop] - oldPos[start],
+			NewStart: lineStart(newPos[start], newPos[stop]),
+			NewLines: newPos[stop] - newPos[start],
+			Edits:    edits[start:stop],
+		})
+		i = stop
+	}
+	return hunks
+}
+
+// lineStart unified形式の開始行（行が無い場合は直前の行番号）
+func lineStart(start, stop int) int {
+	if start == stop {
+		return start
+	}
+	return start + 1
+}
+
+// WriteHeader 比較するファイルの見出しを書き出す
+func WriteHeader(w io.Writer, from, to string, opts Options) {
+	fmt.Fprintln(w, opts.paint("--- "+from, colorBold))
+	fmt.Fprintln(w, opts.paint("+++ "+to, colorBold))
+}
+
+// WriteFields 値が異なるメタデータを書き出す
+func WriteFields(w io.Writer, fields []Field, opts Options) {
+	for _, f := range fields {
+		if f.Old == f.New {
+			continue
+		}
+		fmt.Fprintln(w, opts.paint(fmt.Sprintf("-%s: %s", f.Name, f.Old), colorRed))
+		fmt.Fprintln(w, opts.paint(fmt.Sprintf("+%s: %s", f.Name, f.New), colorGreen))
+	}
+}
+
+// WriteUnified 行単位の差分をunified形式で書き出す（差分が無ければ何も書かない）
+func WriteUnified(w io.Writer, edits []Edit, opts Options) {
+	for _, h := range Hunks(edits, opts.Context) {
+		fmt.Fprintln(w, opts.paint(fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines), colorCyan))
+		if opts.Words {
+			writeWords(w, h.Edits, opts)
+			continue
+		}
+		for _, e := range h.Edits {
+			switch e.Op {
+			case Equal:
+				fmt.Fprintln(w, " "+e.Text)
+			case Delete:
+				fmt.Fprintln(w, opts.paint("-"+e.Text, colorRed))
+			case Insert:
+				fmt.Fprintln(w, opts.paint("+"+e.Text, colorGreen))
+			}
+		}
+	}
+}
+
+// writeWords 連続して変更された行を単語単位の差分で書き出す
+// 色を付けない場合は git diff --word-diff と同じく [-削除-] {+追加+} で示す
+func writeWords(w io.Writer, edits []Edit, opts Options) {
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			fmt.Fprintln(w, edits[i].Text)
+			i++
+			continue
+		}
+		var deleted, inserted []string
+		for ; i < len(edits) && edits[i].Op != Equal; i++ {
+			if edits[i].Op == Delete {
+				deleted = append(deleted, edits[i].Text)
+			} else {
+				inserted = append(inserted, edits[i].Text)
+			}
+		}
+		var b strings.Builder
+		for _, e := range Words(strings.Join(deleted, "\n"), strings.Join(inserted, "\n")) {
+			switch {
+			case e.Op == Equal:
+				b.WriteString(e.Text)
+			case opts.Color && e.Op == Delete:
+				b.WriteString(opts.paint(e.Text, colorRed))
+			case opts.Color:
+				b.WriteString(opts.paint(e.Text, colorGreen))
+			case e.Op == Delete:
+				b.WriteString("[-" + e.Text + "-]")
+			default:
+				b.WriteString("{+" + e.Text + "+}")
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// paint 行ごとに色を付ける（端末で行をまたいで色が残らないようにする）
+func (o Options) paint(s, color string) string {
+	if !o.Color || s == "" {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = color + line + colorReset
+		}
+	}
+	return strings.Join(lines, "\n")
+}
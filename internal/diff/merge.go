@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrConflictMarkers 競合マーカーが残っている
+var ErrConflictMarkers = errors.New("競合マーカー（<<<<<<< 〜 >>>>>>>）が残っています")
+
+// 競合マーカー（git と同じ形式）
+const (
+	markerLocal  = "<<<<<<<"
+	markerSep    = "======="
+	markerRemote = ">>>>>>>"
+)
+
+// change ベースの [start, end) 行を lines に置き換える変更
+type change struct {
+	start, end int
+	lines      []string
+}
+
+// Merge ベース・ローカル・リモートの3つのテキストを行単位でマージする
+// 片方だけの変更と、両方で同じ内容にした変更はそのまま取り込み、
+// 両方で異なる内容に変更した部分（隣接する変更を含む）は git と同じ競合マーカーで囲む
+// 戻り値は、マージした結果と競合の数
+func Merge(base, local, remote, localLabel, remoteLabel string) (string, int) {
+	baseLines := splitLines(base)
+	ours := changes(Lines(base, local))
+	theirs := changes(Lines(base, remote))
+
+	var out []string
+	conflicts := 0
+	pos := 0
+	for len(ours) > 0 || len(theirs) > 0 {
+		// 次の変更と、それに重なる（または隣接する）変更をまとめる
+		first := theirs
+		if len(theirs) == 0 || (len(ours) > 0 && ours[0].start <= theirs[0].start) {
+			first = ours
+		}
+		start, end := first[0].start, first[0].end
+		var o, t []change
+		for {
+			switch {
+			case len(ours) > 0 && ours[0].start <= end:
+				end = max(end, ours[0].end)
+				o, ours = append(o, ours[0]), ours[1:]
+				continue
+			case len(theirs) > 0 && theirs[0].start <= end:
+				end = max(end, theirs[0].end)
+				t, theirs = append(t, theirs[0]), theirs[1:]
+				continue
+			}
+			break
+		}
+
+		out = append(out, baseLines[pos:start]...)
+		oursLines := applyChanges(baseLines, start, end, o)
+		theirsLines := applyChanges(baseLines, start, end, t)
+		switch {
+		case len(t) == 0:
+			out = append(out, oursLines...)
+		case len(o) == 0 || equalLines(oursLines, theirsLines):
+			out = append(out, theirsLines...)
+		default:
+			conflicts++
+			out = append(out, markerLocal+" "+localLabel)
+			out = append(out, oursLines...)
+			out = append(out, markerSep)
+			out = append(out, theirsLines...)
+			out = append(out, markerRemote+" "+remoteLabel)
+		}
+		pos = end
+	}
+	out = append(out, baseLines[pos:]...)
+
+	merged := strings.Join(out, "\n")
+	if merged != "" && strings.HasSuffix(strings.ReplaceAll(local, "\r\n", "\n"), "\n") {
+		merged += "\n"
+	}
+	return merged, conflicts
+}
+
+// HasConflictMarkers テキストに競合マーカーが残っているか
+func HasConflictMarkers(s string) bool {
+	local, remote := false, false
+	for _, line := range splitLines(s) {
+		switch {
+		case strings.HasPrefix(line, markerLocal+" "):
+			local = true
+		case strings.HasPrefix(line, markerRemote+" ") && local:
+			remote = true
+		}
+	}
+	return local && remote
+}
+
+// CheckConflictMarkers アップロードする本文に競合マーカーが残っていれば ErrConflictMarkers を返す
+// 競合を解消していないファイルをアップロードしないよう、記事を作成・更新する前に確認する
+func CheckConflictMarkers(body string) error {
+	if HasConflictMarkers(body) {
+		return ErrConflictMarkers
+	}
+	return nil
+}
+
+// changes 行単位の差分をベースに対する変更の一覧にする
+func changes(edits []Edit) []change {
+	var result []change
+	pos := 0
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			pos++
+			i++
+			continue
+		}
+		c := change{start: pos, end: pos}
+		for ; i < len(edits) && edits[i].Op != Equal; i++ {
+			if edits[i].Op == Delete {
+				c.end++
+			} else {
+				c.lines = append(c.lines, edits[i].Text)
+			}
+		}
+		pos = c.end
+		result = append(result, c)
+	}
+	return result
+}
+
+// applyChanges ベースの [start, end) 行に変更を適用した結果
+func applyChanges(base []string, start, end int, cs []change) []string {
+	var out []string
+	pos := start
+	for _, c := range cs {
+		out = append(out, base[pos:c.start]...)
+		out = append(out, c.lines...)
+		pos = c.end
+	}
+	return append(out, base[pos:end]...)
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package diff
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	base := "# 手順\n1. 準備\n2. 実行\n3. 確認\n"
+	tests := []struct {
+		name          string
+		local, remote string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "正常系：離れた変更は両方取り込む",
+			local:  "# 手順\n1. 準備する\n2. 実行\n3. 確認\n",
+			remote: "# 手順\n1. 準備\n2. 実行\n3. 確認\n4. 片付け\n",
+			want:   "# 手順\n1. 準備する\n2. 実行\n3. 確認\n4. 片付け\n",
+		},
+		{
+			name:   "正常系：片方だけの変更",
+			local:  base,
+			remote: "# 手順書\n1. 準備\n2. 実行\n3. 確認\n",
+			want:   "# 手順書\n1. 準備\n2. 実行\n3. 確認\n",
+		},
+		{
+			name:   "正常系：両方で同じ変更",
+			local:  "# 手順\n1. 準備\n2. 実行する\n3. 確認\n",
+			remote: "# 手順\n1. 準備\n2. 実行する\n3. 確認\n",
+			want:   "# 手順\n1. 準備\n2. 実行する\n3. 確認\n",
+		},
+		{
+			name:          "異常系：同じ行を異なる内容に変更すると競合する",
+			local:         "# 手順\n1. 準備\n2. 実行（ローカル）\n3. 確認\n",
+			remote:        "# 手順\n1. 準備\n2. 実行（リモート）\n3. 確認\n",
+			want:          "# 手順\n1. 準備\n<<<<<<< ローカル\n2. 実行（ローカル）\n=======\n2. 実行（リモート）\n>>>>>>> リモート\n3. 確認\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "エッジケース：同じ位置への異なる追加は競合する",
+			local:         base + "4. ローカル\n",
+			remote:        base + "4. リモート\n",
+			want:          "# 手順\n1. 準備\n2. 実行\n3. 確認\n<<<<<<< ローカル\n4. ローカル\n=======\n4. リモート\n>>>>>>> リモート\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge(base, tt.local, tt.remote, "ローカル", "リモート")
+			if got != tt.want {
+				t.Errorf("Merge() = %q, want %q", got, tt.want)
+			}
+			if conflicts != tt.wantConflicts {
+				t.Errorf("Merge() conflicts = %d, want %d", conflicts, tt.wantConflicts)
+			}
+			if HasConflictMarkers(got) != (tt.wantConflicts > 0) {
+				t.Errorf("HasConflictMarkers() = %v, want %v", HasConflictMarkers(got), tt.wantConflicts > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// Status チェック結果の状態
+type Status string
+
+const (
+	StatusOK    Status = "ok"
+	StatusWarn  Status = "warn"
+	StatusError Status = "error"
+	StatusSkip  Status = "skip"
+)
+
+// 許容する時刻のずれ
+const maxClockSkew = 5 * time.Minute
+
+// Check 1項目のチェック結果
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// Report 全チェックの結果
+type Report struct {
+	OK     bool    `json:"ok"`
+	Checks []Check `json:"checks"`
+}
+
+// Client 診断に使うAPIクライアント
+type Client interface {
+	TokenInfo(ctx context.Context) (*types.TokenInfo, error)
+	GetTeam(ctx context.Context) (*types.Team, time.Time, error)
+}
+
+// Options 診断の入力
+type Options struct {
+	// ConfigPath 設定ファイルのパス
+	ConfigPath string
+	// Config 読み込んだ設定（読み込みに失敗した場合は nil）
+	Config *config.Config
+	// ConfigErr 設定の読み込みエラー
+	ConfigErr error
+	// NewClient APIクライアントを生成する関数
+	NewClient func(team, token string) Client
+	// WorkDir ワークスペースとして確認するディレクトリ
+	WorkDir string
+	// Now 現在時刻（テスト用、nilなら time.Now）
+	Now func() time.Time
+}
+
+// Run すべてのチェックを実行する
+func Run(ctx context.Context, opts Options) *Report {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	report := &Report{}
+	report.add(checkConfigFile(opts))
+	credentials := checkCredentials(opts)
+	report.add(credentials)
+
+	if credentials.Status == StatusOK && opts.NewClient != nil {
+		client := opts.NewClient(opts.Config.TeamName, opts.Config.AccessToken)
+		report.add(checkToken(ctx, client))
+		team, skew := checkTeam(ctx, client, opts.Now)
+		report.add(team)
+		report.add(skew)
+	} else {
+		for _, name := range []string{"token", "team", "clock_skew"} {
+			report.add(Check{Name: name, Status: StatusSkip, Message: "接続情報が無いためスキップしました"})
+		}
+	}
+
+	report.add(checkWorkspace(opts.WorkDir))
+
+	report.OK = true
+	for _, c := range report.Checks {
+		if c.Status == StatusError {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+func (r *Report) add(c Check) {
+	r.Checks = append(r.Checks, c)
+}
+
+func checkConfigFile(opts Options) Check {
+	check := Check{Name: "config_file"}
+	info, err := os.Stat(opts.ConfigPath)
+	if err != nil {
+		if opts.Config != nil {
+			// 環境変数のみで設定されている
+			check.Status = StatusOK
+			check.Message = "設定ファイルはありませんが、環境変数で設定されています"
+			return check
+		}
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("設定ファイルが見つかりません: %s", opts.ConfigPath)
+		check.Fix = "esa-cli setup --team <チーム名> --token-stdin で設定してください"
+		return check
+	}
+	if opts.ConfigErr != nil {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("設定の読み込みに失敗しました: %v", opts.ConfigErr)
+		check.Fix = "設定ファイルのJSONやプロファイル名を確認するか、esa-cli setup を再実行してください"
+		return check
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("設定ファイルが他のユーザーから読み取れます (%04o): %s", perm, opts.ConfigPath)
+		check.Fix = fmt.Sprintf("chmod 600 %s", opts.ConfigPath)
+		return check
+	}
+	check.Status = StatusOK
+	check.Message = opts.ConfigPath
+	return check
+}
+
+func checkCredentials(opts Options) Check {
+	check := Check{Name: "credentials"}
+	cfg := opts.Config
+	switch {
+	case cfg == nil:
+		check.Status = StatusError
+		check.Message = "設定を読み込めませんでした"
+		check.Fix = "esa-cli setup で初期設定を行ってください"
+	case cfg.TeamName == "":
+		check.Status = StatusError
+		check.Message = "チーム名が設定されていません"
+		check.Fix = "esa-cli profile list でプロファイルを確認するか、ESA_TEAM を設定してください"
+	case cfg.AccessToken == "":
+		check.Status = StatusError
+		check.Message = "アクセストークンが設定されていません"
+		check.Fix = "esa-cli setup を再実行するか、ESA_ACCESS_TOKEN を設定してください"
+	default:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("チーム: %s (トークン: %s)", cfg.TeamName, cfg.Origin("access_token"))
+	}
+	return check
+}
+
+func checkToken(ctx context.Context, client Client) Check {
+	check := Check{Name: "token"}
+	info, err := client.TokenInfo(ctx)
+	if err != nil {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("トークンの確認に失敗しました: %v", err)
+		check.Fix = "https://{your-team}.esa.io/user/applications で新しいトークンを発行し、esa-cli setup を再実行してください"
+		return check
+	}
+
+	var missing []string
+	for _, want := range []string{"read", "write"} {
+		if !hasScope(info.Scope, want) {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("トークンに必要なスコープがありません: %s (現在: %s)", strings.Join(missing, ", "), strings.Join(info.Scope, ", "))
+		check.Fix = "read と write のスコープを付けたトークンを発行し直してください"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("スコープ: %s", strings.Join(info.Scope, ", "))
+	return check
+}
+
+func hasScope(scopes []string, want string) bool {
+	for _, s := range scopes {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTeam(ctx context.Context, client Client, now func() time.Time) (Check, Check) {
+	team := Check{Name: "team"}
+	skew := Check{Name: "clock_skew"}
+
+	info, serverTime, err := client.GetTeam(ctx)
+	if err != nil {
+		team.Status = StatusError
+		team.Message = fmt.Sprintf("チームに接続できません: %v", err)
+		team.Fix = "チーム名（サブドメイン）とネットワーク接続を確認してください"
+	} else {
+		team.Status = StatusOK
+		team.Message = fmt.Sprintf("%s (%s)", info.Name, info.URL)
+	}
+
+	if serverTime.IsZero() {
+		skew.Status = StatusSkip
+		skew.Message = "サーバー時刻を取得できませんでした"
+		return team, skew
+	}
+	diff := now().Sub(serverTime)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > maxClockSkew {
+		skew.Status = StatusWarn
+		skew.Message = fmt.Sprintf("ローカル時刻がサーバーと %s ずれています", diff.Round(time.Second))
+		skew.Fix = "OSの時刻同期（NTP）を有効にしてください。ずれがあると更新の競合検出が誤動作します"
+		return team, skew
+	}
+	skew.Status = StatusOK
+	skew.Message = fmt.Sprintf("ずれ %s", diff.Round(time.Second))
+	return team, skew
+}
+
+func checkWorkspace(dir string) Check {
+	check := Check{Name: "workspace"}
+	if dir == "" {
+		check.Status = StatusSkip
+		check.Message = "ワークスペースが指定されていません"
+		return check
+	}
+
+	// 書き込み権限
+	f, err := os.CreateTemp(dir, ".esa-cli-doctor-*")
+	if err != nil {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("ディレクトリに書き込めません: %s", dir)
+		check.Fix = "書き込み可能なディレクトリで実行してください"
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	// プロジェクト設定
+	if _, err := config.FindProjectConfig(dir); err != nil {
+		check.Status = StatusError
+		check.Message = err.Error()
+		check.Fix = fmt.Sprintf("%s のYAMLを修正してください", config.ProjectFileName)
+		return check
+	}
+
+	// Front Matterを解析できないファイル
+	files, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+	var broken []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if _, _, err := markdown.ParseContent(content); err != nil {
+			broken = append(broken, filepath.Base(file))
+		}
+	}
+	if len(broken) > 0 {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Front Matterを解析できないファイルがあります: %s", strings.Join(broken, ", "))
+		check.Fix = "ファイル先頭の --- で囲まれたFront Matterを確認してください"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("%s (Markdownファイル %d件)", dir, len(files))
+	return check
+}
@@ -0,0 +1,141 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/testutil"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+type fakeClient struct {
+	scopes     []string
+	tokenErr   error
+	teamErr    error
+	serverTime time.Time
+}
+
+func (f *fakeClient) TokenInfo(ctx context.Context) (*types.TokenInfo, error) {
+	if f.tokenErr != nil {
+		return nil, f.tokenErr
+	}
+	return &types.TokenInfo{Scope: f.scopes}, nil
+}
+
+func (f *fakeClient) GetTeam(ctx context.Context) (*types.Team, time.Time, error) {
+	if f.teamErr != nil {
+		return nil, f.serverTime, f.teamErr
+	}
+	return &types.Team{Name: "test-team", URL: "https://test-team.esa.io/"}, f.serverTime, nil
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		mode       os.FileMode
+		client     *fakeClient
+		wantOK     bool
+		wantStatus map[string]Status
+	}{
+		{
+			name:   "正常系：すべてのチェックが通る",
+			mode:   0600,
+			client: &fakeClient{scopes: []string{"read", "write"}, serverTime: now},
+			wantOK: true,
+			wantStatus: map[string]Status{
+				"config_file": StatusOK,
+				"token":       StatusOK,
+				"team":        StatusOK,
+				"clock_skew":  StatusOK,
+				"workspace":   StatusOK,
+			},
+		},
+		{
+			name:   "正常系：パーミッションとスコープと時刻のずれは警告になる",
+			mode:   0644,
+			client: &fakeClient{scopes: []string{"read"}, serverTime: now.Add(-10 * time.Minute)},
+			wantOK: true,
+			wantStatus: map[string]Status{
+				"config_file": StatusWarn,
+				"token":       StatusWarn,
+				"clock_skew":  StatusWarn,
+			},
+		},
+		{
+			name:   "異常系：トークンが無効でチームに接続できない",
+			mode:   0600,
+			client: &fakeClient{tokenErr: errors.New("認証エラー"), teamErr: errors.New("not found")},
+			wantOK: false,
+			wantStatus: map[string]Status{
+				"token":      StatusError,
+				"team":       StatusError,
+				"clock_skew": StatusSkip,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			tmpDir := testutil.CreateTempDir(t)
+			configPath := filepath.Join(tmpDir, "config.json")
+			if err := os.WriteFile(configPath, []byte("{}"), tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			os.Chmod(configPath, tt.mode)
+			testutil.CreateTestPostFile(t, tmpDir, 1, "テスト記事")
+
+			opts := Options{
+				ConfigPath: configPath,
+				Config:     &config.Config{TeamName: "test-team", AccessToken: "test-token"},
+				NewClient:  func(team, token string) Client { return tt.client },
+				WorkDir:    tmpDir,
+				Now:        func() time.Time { return now },
+			}
+
+			// When
+			report := Run(context.Background(), opts)
+
+			// Then
+			if report.OK != tt.wantOK {
+				t.Errorf("Run().OK = %v, want %v (%+v)", report.OK, tt.wantOK, report.Checks)
+			}
+			got := map[string]Status{}
+			for _, c := range report.Checks {
+				got[c.Name] = c.Status
+				if (c.Status == StatusWarn || c.Status == StatusError) && c.Fix == "" {
+					t.Errorf("check %s has no fix", c.Name)
+				}
+			}
+			for name, want := range tt.wantStatus {
+				if got[name] != want {
+					t.Errorf("check %s = %v, want %v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_NoConfig(t *testing.T) {
+	tmpDir := testutil.CreateTempDir(t)
+
+	report := Run(context.Background(), Options{
+		ConfigPath: filepath.Join(tmpDir, "missing.json"),
+		ConfigErr:  errors.New("設定ファイルが見つかりません"),
+		WorkDir:    tmpDir,
+	})
+
+	if report.OK {
+		t.Errorf("Run().OK = true, want false")
+	}
+	if report.Checks[0].Status != StatusError {
+		t.Errorf("config_file = %v, want error", report.Checks[0].Status)
+	}
+}
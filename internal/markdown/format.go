@@ -0,0 +1,462 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a front matter format.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+	FormatJSON Format = "json"
+)
+
+const (
+	yamlDelimiter = "---"
+	tomlDelimiter = "+++"
+)
+
+// ParseFormat converts a format name into a Format. An empty name means YAML.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return FormatYAML, nil
+	case FormatYAML, FormatTOML, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown front matter format: %s (yaml, toml, json)", name)
+}
+
+// delimiter returns the line that encloses the front matter, or "" for JSON.
+func (f Format) delimiter() string {
+	switch f {
+	case FormatTOML:
+		return tomlDelimiter
+	case FormatJSON:
+		return ""
+	}
+	return yamlDelimiter
+}
+
+// decodeNode parses raw front matter of format f into a YAML document node.
+// Front matter in every format is handled as a YAML node tree so that keys
+// keep their order and unknown keys survive a rewrite.
+func decodeNode(f Format, raw []byte) (*yaml.Node, error) {
+	switch f {
+	case FormatTOML:
+		return decodeTOML(raw)
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		m, err := decodeJSON(dec)
+		if err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{m}}, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return nil, yamlError(raw, err)
+	}
+	return &node, nil
+}
+
+// yamlError reports err with the line where the YAML breaks.
+// yaml.v3 reports parser errors at the zero-based line where the enclosing
+// node starts, so the line is found again as the first line at which the
+// leading lines of raw fail with the same message.
+func yamlError(raw []byte, err error) error {
+	m := linePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	lines := bytes.SplitAfter(raw, []byte("\n"))
+	for n := 1; n <= len(lines); n++ {
+		var node yaml.Node
+		prefixErr := yaml.Unmarshal(bytes.Join(lines[:n], nil), &node)
+		if prefixErr == nil {
+			continue
+		}
+		if pm := linePattern.FindStringSubmatch(prefixErr.Error()); pm != nil && pm[2] == m[2] {
+			return fmt.Errorf("line %d: %s", n, m[2])
+		}
+	}
+	return err
+}
+
+// encodeNode renders a YAML document node in format f.
+func encodeNode(f Format, node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	switch f {
+	case FormatTOML:
+		if err := writeTOMLTable(&buf, node.Content[0], nil); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		writeJSON(&buf, node.Content[0], "")
+		buf.WriteString("\n")
+		return buf.Bytes(), nil
+	}
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeTOML parses TOML into a node tree, keeping the key order of the file.
+func decodeTOML(raw []byte) (*yaml.Node, error) {
+	var values map[string]interface{}
+	md, err := toml.Decode(string(raw), &values)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("line %d: %s", parseErr.Position.Line, parseErr.Message)
+		}
+		return nil, err
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range md.Keys() {
+		parent := root
+		value := interface{}(values)
+		for i, k := range key {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				parent = nil
+				break
+			}
+			value = m[k]
+			if i == len(key)-1 {
+				break
+			}
+			if parent = mappingValue(parent, k); parent == nil {
+				break
+			}
+		}
+		if parent == nil {
+			// Keys inside arrays of tables are already part of their array.
+			continue
+		}
+		last := key[len(key)-1]
+		if _, ok := value.(map[string]interface{}); ok && mappingValue(parent, last) != nil {
+			continue
+		}
+		// Re-append so that keys end up in the order they appear in the file.
+		removeKey(parent, last)
+		n, err := toNode(value)
+		if err != nil {
+			return nil, err
+		}
+		parent.Content = append(parent.Content, stringNode(last), n)
+	}
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, nil
+}
+
+// mappingValue returns the mapping node stored under key in m, if any.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			if m.Content[i+1].Kind == yaml.MappingNode {
+				return m.Content[i+1]
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func removeKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// toNode converts a decoded TOML value into a node. Nested maps are sorted by key.
+func toNode(v interface{}) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range keys {
+			n, err := toNode(v[k])
+			if err != nil {
+				return nil, err
+			}
+			m.Content = append(m.Content, stringNode(k), n)
+		}
+		return m, nil
+	case []map[string]interface{}:
+		s := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			n, err := toNode(item)
+			if err != nil {
+				return nil, err
+			}
+			s.Content = append(s.Content, n)
+		}
+		return s, nil
+	case []interface{}:
+		s := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			n, err := toNode(item)
+			if err != nil {
+				return nil, err
+			}
+			s.Content = append(s.Content, n)
+		}
+		return s, nil
+	case time.Time:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: formatTime(v)}, nil
+	}
+
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// formatTime renders a decoded TOML datetime so that it parses back to the same value.
+// Local dates and times are decoded with a location named after their TOML type.
+func formatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// decodeJSON reads the next JSON value from dec as a node, keeping key order.
+func decodeJSON(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.Content = append(m.Content, stringNode(fmt.Sprint(key)), value)
+			}
+			_, err := dec.Token()
+			return m, err
+		}
+		s := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			s.Content = append(s.Content, value)
+		}
+		_, err := dec.Token()
+		return s, err
+	case string:
+		return stringNode(t), nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+// writeJSON writes n as indented JSON.
+func writeJSON(buf *bytes.Buffer, n *yaml.Node, indent string) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			buf.WriteString(indent + "  ")
+			buf.WriteString(jsonString(n.Content[i].Value))
+			buf.WriteString(": ")
+			writeJSON(buf, n.Content[i+1], indent+"  ")
+			if i+2 < len(n.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i, item := range n.Content {
+			buf.WriteString(indent + "  ")
+			writeJSON(buf, item, indent+"  ")
+			if i+1 < len(n.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+	case yaml.AliasNode:
+		writeJSON(buf, n.Alias, indent)
+	default:
+		switch n.ShortTag() {
+		case "!!int", "!!float", "!!bool":
+			buf.WriteString(n.Value)
+		case "!!null":
+			buf.WriteString("null")
+		default:
+			buf.WriteString(jsonString(n.Value))
+		}
+	}
+}
+
+// jsonString quotes s as a JSON string. The escapes are also valid in TOML basic strings.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return jsonString(key)
+}
+
+// isTableArray reports whether n is a non-empty sequence of mappings.
+func isTableArray(n *yaml.Node) bool {
+	if n.Kind != yaml.SequenceNode || len(n.Content) == 0 {
+		return false
+	}
+	for _, item := range n.Content {
+		if item.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTOMLTable writes the keys of m, then its sub tables.
+func writeTOMLTable(buf *bytes.Buffer, m *yaml.Node, path []string) error {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		value := m.Content[i+1]
+		if value.Kind == yaml.MappingNode || isTableArray(value) || value.ShortTag() == "!!null" {
+			continue
+		}
+		v, err := tomlValue(value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "%s = %s\n", tomlKey(m.Content[i].Value), v)
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key, value := m.Content[i].Value, m.Content[i+1]
+		sub := append(append([]string{}, path...), tomlKey(key))
+		switch {
+		case value.Kind == yaml.MappingNode:
+			fmt.Fprintf(buf, "\n[%s]\n", strings.Join(sub, "."))
+			if err := writeTOMLTable(buf, value, sub); err != nil {
+				return err
+			}
+		case isTableArray(value):
+			for _, item := range value.Content {
+				fmt.Fprintf(buf, "\n[[%s]]\n", strings.Join(sub, "."))
+				if err := writeTOMLTable(buf, item, sub); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tomlValue renders an inline TOML value.
+func tomlValue(n *yaml.Node) (string, error) {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, v)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case yaml.MappingNode:
+		items := make([]string, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v, err := tomlValue(n.Content[i+1])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, tomlKey(n.Content[i].Value)+" = "+v)
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	case yaml.AliasNode:
+		return tomlValue(n.Alias)
+	}
+
+	switch n.ShortTag() {
+	case "!!int", "!!float", "!!bool", "!!timestamp":
+		return n.Value, nil
+	case "!!null":
+		return "", fmt.Errorf("TOML cannot represent null values")
+	}
+	return jsonString(n.Value), nil
+}
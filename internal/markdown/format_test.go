@@ -0,0 +1,217 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestParseContent_Formats(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFormat Format
+		wantBody   string
+	}{
+		{
+			name:       "正常系：YAML",
+			content:    "---\ntitle: テスト\ntags: [go]\nnumber: 1\n---\n\n本文\n",
+			wantFormat: FormatYAML,
+			wantBody:   "本文\n",
+		},
+		{
+			name:       "正常系：TOML",
+			content:    "+++\ntitle = \"テスト\"\ntags = [\"go\"]\nnumber = 1\n+++\n\n本文\n",
+			wantFormat: FormatTOML,
+			wantBody:   "本文\n",
+		},
+		{
+			name:       "正常系：JSON",
+			content:    "{\n  \"title\": \"テスト\",\n  \"tags\": [\"go\"],\n  \"number\": 1\n}\n\n本文\n",
+			wantFormat: FormatJSON,
+			wantBody:   "本文\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if doc.Format != tt.wantFormat {
+				t.Errorf("Format = %q, want %q", doc.Format, tt.wantFormat)
+			}
+
+			fm, body, err := ParseContent([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseContent() error = %v", err)
+			}
+			want := types.FrontMatter{Title: "テスト", Tags: []string{"go"}, Number: 1}
+			if !reflect.DeepEqual(fm, want) {
+				t.Errorf("FrontMatter = %+v, want %+v", fm, want)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestUpdateContent_KeepsFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "正常系：TOMLのまま書き換え、独自のキーとテーブルを保持する",
+			content: "+++\ndraft = true\ntitle = \"古いタイトル\"\n\n[params]\nowner = \"alice\"\n+++\n\n本文\n",
+			want:    "+++\ndraft = true\ntitle = \"新しいタイトル\"\ncategory = \"開発\"\ntags = [\"go\"]\nwip = false\nnumber = 1\n\n[params]\nowner = \"alice\"\n+++\n\n新しい本文\n",
+		},
+		{
+			name:    "正常系：JSONのまま書き換え、独自のキーの順序を保持する",
+			content: "{\n  \"draft\": true,\n  \"title\": \"古いタイトル\"\n}\n\n本文\n",
+			want:    "{\n  \"draft\": true,\n  \"title\": \"新しいタイトル\",\n  \"category\": \"開発\",\n  \"tags\": [\n    \"go\"\n  ],\n  \"wip\": false,\n  \"number\": 1\n}\n\n新しい本文\n",
+		},
+	}
+
+	fm := types.FrontMatter{Title: "新しいタイトル", Category: "開発", Tags: []string{"go"}, Number: 1}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateContent([]byte(tt.content), fm, "新しい本文\n")
+			if err != nil {
+				t.Fatalf("UpdateContent() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("UpdateContent() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateContent_TOMLDatetimes(t *testing.T) {
+	content := "+++\n" +
+		"title = \"古いタイトル\"\n" +
+		"date = 2024-01-01T10:00:00+09:00\n" +
+		"utc = 2024-01-01T01:00:00.5Z\n" +
+		"published = 2024-01-02\n" +
+		"local = 2024-01-03T08:30:00\n" +
+		"at = 07:15:00\n" +
+		"+++\n\n本文\n"
+	want := []string{
+		"date = 2024-01-01T10:00:00+09:00\n",
+		"utc = 2024-01-01T01:00:00.5Z\n",
+		"published = 2024-01-02\n",
+		"local = 2024-01-03T08:30:00\n",
+		"at = 07:15:00\n",
+	}
+
+	fm := types.FrontMatter{Title: "新しいタイトル"}
+	got, err := UpdateContent([]byte(content), fm, "本文\n")
+	if err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	for _, line := range want {
+		if !strings.Contains(string(got), line) {
+			t.Errorf("UpdateContent() =\n%s\nwant to contain %q", got, line)
+		}
+	}
+
+	// 書き換えた内容を読み直して、もう一度書き換えても同じになる
+	again, err := UpdateContent(got, fm, "本文\n")
+	if err != nil {
+		t.Fatalf("再度の UpdateContent() error = %v\n%s", err, got)
+	}
+	if string(again) != string(got) {
+		t.Errorf("往復で変わりました:\n%s\nwant\n%s", again, got)
+	}
+}
+
+func TestGenerateContentAs(t *testing.T) {
+	fm := types.FrontMatter{Title: "テスト", Tags: []string{"a", "b"}}
+	for _, format := range []Format{FormatYAML, FormatTOML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			content, err := GenerateContentAs(format, fm, "本文")
+			if err != nil {
+				t.Fatalf("GenerateContentAs() error = %v", err)
+			}
+			doc, err := Parse(content)
+			if err != nil {
+				t.Fatalf("Parse() error = %v\n%s", err, content)
+			}
+			if doc.Format != format {
+				t.Errorf("Format = %q, want %q", doc.Format, format)
+			}
+			var got types.FrontMatter
+			if err := doc.Decode(&got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got.Title != fm.Title || !reflect.DeepEqual(got.Tags, fm.Tags) || doc.Body != "本文" {
+				t.Errorf("往復できません: %+v, %q", got, doc.Body)
+			}
+		})
+	}
+}
+
+func TestParse_FormatErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{name: "異常系：不正なTOML", content: "+++\ntitle = \"a\"\ntags = [\n+++\n", line: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("ParseError が返されていません: %v", err)
+			}
+			if parseErr.Line != tt.line {
+				t.Errorf("エラー行 = %d, want %d (%v)", parseErr.Line, tt.line, err)
+			}
+		})
+	}
+}
+
+func TestParse_BraceWithoutJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "正常系：Hugoのショートコードで始まる本文", content: "{{< note >}}\n注意\n{{< /note >}}\n"},
+		{name: "正常系：波括弧で始まる段落", content: "{TODO} あとで書く\n"},
+		{name: "エッジケース：閉じていないJSON", content: "{\n  \"title\": \"a\",\n  oops\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if doc.HasFrontMatter || doc.Body != tt.content {
+				t.Errorf("Front Matterの無い本文として扱われていません: %+v", doc)
+			}
+			if _, err := UpdateContent([]byte(tt.content), types.FrontMatter{Title: "タイトル"}, tt.content); err != nil {
+				t.Errorf("UpdateContent() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"", "yaml", "TOML", "json"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("ParseFormat(\"xml\") error = %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/shellme/esa-cli/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// parseNode parses the raw front matter into a YAML node tree.
+func (d *Document) parseNode() error {
+	d.node = nil
+	if len(bytes.TrimSpace(d.FrontMatter)) == 0 {
+		return nil
+	}
+
+	node, err := decodeNode(d.Format, d.FrontMatter)
+	if err != nil {
+		return d.wrapError(err)
+	}
+	if len(node.Content) == 0 {
+		return nil
+	}
+	if node.Content[0].Kind != yaml.MappingNode {
+		return &ParseError{Line: d.line, Msg: "front matter must be a mapping"}
+	}
+	d.node = node
+	return nil
+}
+
+// mapping returns the top-level mapping node, creating it if needed.
+func (d *Document) mapping() *yaml.Node {
+	if d.node == nil {
+		d.node = &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+	return d.node.Content[0]
+}
+
+// find returns the index of the key node for key, or -1.
+func (d *Document) find(key string) int {
+	if d.node == nil {
+		return -1
+	}
+	m := d.node.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Keys returns the front matter keys in file order.
+func (d *Document) Keys() []string {
+	if d.node == nil {
+		return nil
+	}
+	m := d.node.Content[0]
+	keys := make([]string, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		keys = append(keys, m.Content[i].Value)
+	}
+	return keys
+}
+
+// Get returns the value of a front matter key.
+func (d *Document) Get(key string) (interface{}, bool) {
+	i := d.find(key)
+	if i < 0 {
+		return nil, false
+	}
+	var v interface{}
+	if err := d.node.Content[0].Content[i+1].Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// Set sets a front matter key, keeping the position and comments of an existing key.
+// New keys are appended at the end.
+func (d *Document) Set(key string, value interface{}) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %v", key, err)
+	}
+	d.setNode(key, &encoded)
+	return d.sync()
+}
+
+// Delete removes a front matter key. It reports whether the key existed.
+func (d *Document) Delete(key string) bool {
+	i := d.find(key)
+	if i < 0 {
+		return false
+	}
+	m := d.node.Content[0]
+	m.Content = append(m.Content[:i], m.Content[i+2:]...)
+	_ = d.sync()
+	return true
+}
+
+// SetFrontMatter writes the fields of fm into the front matter.
+// Keys unknown to types.FrontMatter are left untouched, and known keys that
+// fm omits are removed.
+func (d *Document) SetFrontMatter(fm types.FrontMatter) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(fm); err != nil {
+		return err
+	}
+
+	present := map[string]bool{}
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		key := encoded.Content[i].Value
+		present[key] = true
+		d.setNode(key, encoded.Content[i+1])
+	}
+	for _, key := range frontMatterKeys() {
+		if !present[key] {
+			if i := d.find(key); i >= 0 {
+				m := d.node.Content[0]
+				m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			}
+		}
+	}
+	return d.sync()
+}
+
+// setNode replaces or appends the value node for key.
+func (d *Document) setNode(key string, value *yaml.Node) {
+	m := d.mapping()
+	if i := d.find(key); i >= 0 {
+		old := m.Content[i+1]
+		value.HeadComment = old.HeadComment
+		value.LineComment = old.LineComment
+		value.FootComment = old.FootComment
+		m.Content[i+1] = value
+		return
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// sync re-encodes the node tree into the raw front matter.
+func (d *Document) sync() error {
+	d.HasFrontMatter = true
+	if len(d.mapping().Content) == 0 && d.Format != FormatJSON {
+		d.FrontMatter = nil
+		return nil
+	}
+
+	out, err := encodeNode(d.Format, d.node)
+	if err != nil {
+		return err
+	}
+	d.FrontMatter = out
+	return nil
+}
+
+// frontMatterKeys returns the keys types.FrontMatter knows about.
+func frontMatterKeys() []string {
+	t := reflect.TypeOf(types.FrontMatter{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// UpdateContent writes fm and body into existing file content.
+// Unknown front matter keys, their order and comments, and the front matter
+// format are preserved. If the front matter of existing cannot be parsed, the
+// error is returned rather than regenerating it and dropping unknown keys.
+func UpdateContent(existing []byte, fm types.FrontMatter, body string) ([]byte, error) {
+	if len(existing) == 0 {
+		return GenerateContent(fm, body)
+	}
+	doc, err := Parse(existing)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.SetFrontMatter(fm); err != nil {
+		return nil, err
+	}
+	doc.Body = body
+	return doc.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+const customContent = `---
+# 記事の管理情報
+owner: alice # 担当者
+title: 古いタイトル
+category: 開発
+tags:
+  - go
+wip: true
+review_due: 2024-05-01
+aliases:
+  - 旧名称
+remote_updated_at: "2024-01-01T00:00:00+09:00"
+---
+
+本文
+`
+
+func TestUpdateContent(t *testing.T) {
+	fm := types.FrontMatter{Title: "新しいタイトル", Category: "開発/設計", Tags: []string{"go", "cli"}}
+	content, err := UpdateContent([]byte(customContent), fm, "新しい本文\n")
+	if err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	got := string(content)
+
+	for _, want := range []string{"# 記事の管理情報", "owner: alice # 担当者", "review_due: 2024-05-01", "- 旧名称", "title: 新しいタイトル", "- cli", "新しい本文\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q が含まれていません:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "remote_updated_at") {
+		t.Errorf("省略された既知のキーが残っています:\n%s", got)
+	}
+
+	doc, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	wantKeys := []string{"owner", "title", "category", "tags", "wip", "review_due", "aliases"}
+	if keys := doc.Keys(); !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("Keys() = %v, want %v", keys, wantKeys)
+	}
+}
+
+func TestUpdateContent_InvalidFrontMatter(t *testing.T) {
+	existing := "---\ntitle: テスト\nowner: alice\ntags: [go\n---\n\n本文\n"
+	_, err := UpdateContent([]byte(existing), types.FrontMatter{Title: "テスト"}, "本文\n")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("UpdateContent() error = %v, want ParseError（独自のキーを消して作り直さない）", err)
+	}
+}
+
+func TestUpdateContent_NewFile(t *testing.T) {
+	content, err := UpdateContent(nil, types.FrontMatter{Title: "テスト"}, "本文")
+	if err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	if want := "---\ntitle: テスト\ncategory: \"\"\ntags: []\nwip: false\n---\n\n本文"; string(content) != want {
+		t.Errorf("UpdateContent() = %q, want %q", content, want)
+	}
+}
+
+func TestDocument_GetSetDelete(t *testing.T) {
+	doc, err := Parse([]byte(customContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		want   interface{}
+		wantOK bool
+	}{
+		{name: "正常系：未知のキーを取得できる", key: "owner", want: "alice", wantOK: true},
+		{name: "正常系：配列を取得できる", key: "aliases", want: []interface{}{"旧名称"}, wantOK: true},
+		{name: "異常系：存在しないキー", key: "missing", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := doc.Get(tt.key)
+			if ok != tt.wantOK || (ok && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("Get(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if err := doc.Set("owner", "bob"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := doc.Set("priority", 1); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !doc.Delete("aliases") {
+		t.Error("Delete() = false, want true")
+	}
+	if doc.Delete("aliases") {
+		t.Error("削除済みのキーで Delete() = true")
+	}
+
+	got := string(doc.Bytes())
+	if !strings.Contains(got, "owner: bob # 担当者") {
+		t.Errorf("コメントが保持されていません:\n%s", got)
+	}
+	if !strings.HasSuffix(doc.Keys()[len(doc.Keys())-1], "priority") {
+		t.Errorf("新しいキーが末尾に追加されていません: %v", doc.Keys())
+	}
+	if strings.Contains(got, "旧名称") {
+		t.Errorf("削除したキーが残っています:\n%s", got)
+	}
+}
+
+func TestDocument_SetWithoutFrontMatter(t *testing.T) {
+	doc, err := Parse([]byte("本文のみ\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := doc.Set("owner", "alice"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if want := "---\nowner: alice\n---\n\n本文のみ\n"; string(doc.Bytes()) != want {
+		t.Errorf("Bytes() = %q, want %q", doc.Bytes(), want)
+	}
+}
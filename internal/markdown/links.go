@@ -0,0 +1,176 @@
+package markdown
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LinkResolver rewrites links between esa posts and local files.
+type LinkResolver struct {
+	// Team is the esa team name, used to recognize https://<team>.esa.io/posts/N links.
+	Team string
+	// Local returns the local file of a post, if it exists in the workspace.
+	Local func(number int) (string, bool)
+	// Number returns the post number of a local file.
+	Number func(path string) (int, bool)
+}
+
+var (
+	// inlineLinkPattern matches the destination of [text](destination "title").
+	inlineLinkPattern = regexp.MustCompile(`\]\(\s*(<[^>\n]*>|[^)\s]+)`)
+	// referencePattern matches a link reference definition: [id]: destination
+	referencePattern = regexp.MustCompile(`^( {0,3}\[[^\]]+\]:[ \t]*)(<[^>\n]*>|\S+)`)
+	// imgSrcPattern matches the src attribute of an <img> tag.
+	imgSrcPattern = regexp.MustCompile(`(<img\s[^>]*?\bsrc=["'])([^"']+)`)
+	// fencePattern matches the opening or closing line of a fenced code block.
+	fencePattern = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	// postURLPattern matches /posts/N and https://<team>.esa.io/posts/N with an optional fragment.
+	postURLPattern = regexp.MustCompile(`^(?:https?://([a-z0-9-]+)\.esa\.io)?/posts/(\d+)/?(#.*)?$`)
+)
+
+// ToLocal rewrites links to posts that exist locally into relative links
+// to their files. fromDir is the directory of the file the body is written to.
+func (r *LinkResolver) ToLocal(body, fromDir string) string {
+	fromDir = absDir(fromDir)
+	return RewriteLinks(body, func(dest string) string {
+		m := postURLPattern.FindStringSubmatch(dest)
+		if m == nil || (r.Team != "" && m[1] != "" && m[1] != r.Team) {
+			return dest
+		}
+		number, _ := strconv.Atoi(m[2])
+		path, ok := r.Local(number)
+		if !ok {
+			return dest
+		}
+		rel, err := filepath.Rel(fromDir, path)
+		if err != nil {
+			return dest
+		}
+		return formatDestination(filepath.ToSlash(rel) + m[3])
+	})
+}
+
+// ToRemote rewrites relative links to local post files back into /posts/N links.
+func (r *LinkResolver) ToRemote(body, fromDir string) string {
+	fromDir = absDir(fromDir)
+	return RewriteLinks(body, func(dest string) string {
+		path, fragment := strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">"), ""
+		if i := strings.Index(path, "#"); i >= 0 {
+			path, fragment = path[:i], path[i:]
+		}
+		if !strings.HasSuffix(path, ".md") || strings.Contains(path, "://") || strings.HasPrefix(path, "/") {
+			return dest
+		}
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+		number, ok := r.Number(filepath.Join(fromDir, filepath.FromSlash(path)))
+		if !ok {
+			return dest
+		}
+		return fmt.Sprintf("/posts/%d%s", number, fragment)
+	})
+}
+
+func absDir(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// formatDestination wraps a destination in <> when it contains spaces.
+func formatDestination(dest string) string {
+	if strings.ContainsAny(dest, " \t") {
+		return "<" + dest + ">"
+	}
+	return dest
+}
+
+// RewriteLinks applies fn to every link and image destination outside code
+// blocks and code spans, including the src of <img> tags.
+func RewriteLinks(body string, fn func(dest string) string) string {
+	lines := strings.SplitAfter(body, "\n")
+	fence := ""
+	for i, line := range lines {
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case strings.HasPrefix(m[1], fence[:1]) && len(m[1]) >= len(fence):
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		if m := referencePattern.FindStringSubmatchIndex(line); m != nil {
+			lines[i] = line[:m[3]] + fn(line[m[4]:m[5]]) + line[m[5]:]
+			continue
+		}
+		lines[i] = rewriteInline(line, fn)
+	}
+	return strings.Join(lines, "")
+}
+
+// rewriteInline rewrites inline link destinations in a line, skipping code spans.
+func rewriteInline(line string, fn func(dest string) string) string {
+	var b strings.Builder
+	for len(line) > 0 {
+		start := strings.Index(line, "`")
+		if start < 0 {
+			b.WriteString(replaceInline(line, fn))
+			break
+		}
+		b.WriteString(replaceInline(line[:start], fn))
+
+		// A code span ends at the next run of backticks of the same length.
+		ticks := len(line[start:]) - len(strings.TrimLeft(line[start:], "`"))
+		rest := line[start+ticks:]
+		end := indexBacktickRun(rest, ticks)
+		if end < 0 {
+			b.WriteString(line[start : start+ticks])
+			line = rest
+			continue
+		}
+		b.WriteString(line[start : start+ticks+end+ticks])
+		line = rest[end+ticks:]
+	}
+	return b.String()
+}
+
+// indexBacktickRun returns the index of the next run of exactly n backticks.
+func indexBacktickRun(s string, n int) int {
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && s[j] == '`' {
+			j++
+		}
+		if j-i == n {
+			return i
+		}
+		i = j
+	}
+	return -1
+}
+
+func replaceInline(s string, fn func(dest string) string) string {
+	s = inlineLinkPattern.ReplaceAllStringFunc(s, func(match string) string {
+		m := inlineLinkPattern.FindStringSubmatchIndex(match)
+		return match[:m[2]] + fn(match[m[2]:m[3]])
+	})
+	return imgSrcPattern.ReplaceAllStringFunc(s, func(match string) string {
+		m := imgSrcPattern.FindStringSubmatchIndex(match)
+		return match[:m[4]] + fn(match[m[4]:m[5]])
+	})
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestResolver(root string) *LinkResolver {
+	files := map[int]string{
+		12: filepath.Join(root, "12-設計.md"),
+		34: filepath.Join(root, "sub", "34-議事録 1.md"),
+	}
+	return &LinkResolver{
+		Team: "docs",
+		Local: func(number int) (string, bool) {
+			path, ok := files[number]
+			return path, ok
+		},
+		Number: func(path string) (int, bool) {
+			for number, p := range files {
+				if p == path {
+					return number, true
+				}
+			}
+			return 0, false
+		},
+	}
+}
+
+func TestLinkResolver_ToLocal(t *testing.T) {
+	root := t.TempDir()
+	r := newTestResolver(root)
+
+	tests := []struct {
+		name string
+		body string
+		dir  string
+		want string
+	}{
+		{
+			name: "正常系：相対URLの記事リンク",
+			body: "[設計](/posts/12) を参照\n",
+			dir:  root,
+			want: "[設計](12-設計.md) を参照\n",
+		},
+		{
+			name: "正常系：絶対URLとアンカー",
+			body: "[設計](https://docs.esa.io/posts/12#1-1 \"タイトル\")\n",
+			dir:  root,
+			want: "[設計](12-設計.md#1-1 \"タイトル\")\n",
+		},
+		{
+			name: "正常系：サブディレクトリのファイル名に空白を含む",
+			body: "[議事録](/posts/34)\n",
+			dir:  root,
+			want: "[議事録](<sub/34-議事録 1.md>)\n",
+		},
+		{
+			name: "正常系：別ディレクトリからの相対パス",
+			body: "[ref]: /posts/12\n",
+			dir:  filepath.Join(root, "sub"),
+			want: "[ref]: ../12-設計.md\n",
+		},
+		{
+			name: "エッジケース：ローカルに無い記事と別チームのリンクはそのまま",
+			body: "[a](/posts/99) [b](https://other.esa.io/posts/12)\n",
+			dir:  root,
+			want: "[a](/posts/99) [b](https://other.esa.io/posts/12)\n",
+		},
+		{
+			name: "エッジケース：コードブロックとコードスパンは書き換えない",
+			body: "```\n[a](/posts/12)\n```\n`[b](/posts/12)` [c](/posts/12)\n",
+			dir:  root,
+			want: "```\n[a](/posts/12)\n```\n`[b](/posts/12)` [c](12-設計.md)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.ToLocal(tt.body, tt.dir); got != tt.want {
+				t.Errorf("ToLocal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkResolver_ToRemote(t *testing.T) {
+	root := t.TempDir()
+	r := newTestResolver(root)
+
+	tests := []struct {
+		name string
+		body string
+		dir  string
+		want string
+	}{
+		{
+			name: "正常系：ローカルファイルへのリンクを記事リンクに戻す",
+			body: "[設計](12-設計.md#1-1) [議事録](<sub/34-議事録 1.md>)\r\n",
+			dir:  root,
+			want: "[設計](/posts/12#1-1) [議事録](/posts/34)\r\n",
+		},
+		{
+			name: "正常系：URLエンコードされたパス",
+			body: "[ref]: ../12-%E8%A8%AD%E8%A8%88.md\n",
+			dir:  filepath.Join(root, "sub"),
+			want: "[ref]: /posts/12\n",
+		},
+		{
+			name: "エッジケース：ワークスペース外のファイルと外部URLはそのまま",
+			body: "[a](other.md) [b](https://example.com/12-設計.md)\n",
+			dir:  root,
+			want: "[a](other.md) [b](https://example.com/12-設計.md)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.ToRemote(tt.body, tt.dir); got != tt.want {
+				t.Errorf("ToRemote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkResolver_RoundTrip(t *testing.T) {
+	root := t.TempDir()
+	r := newTestResolver(root)
+	body := "# 関連\n\n- [設計](/posts/12)\n- [議事録](/posts/34#決定事項)\n"
+
+	if got := r.ToRemote(r.ToLocal(body, root), root); got != body {
+		t.Errorf("往復後 = %q, want %q", got, body)
+	}
+}
@@ -2,49 +2,96 @@ package markdown
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 
 	"github.com/shellme/esa-cli/pkg/types"
-	"gopkg.in/yaml.v2"
 )
 
-// GenerateContent generates markdown content with front matter.
+// GenerateContent generates markdown content with YAML front matter.
+// The body is written as is, and the delimiters follow its line endings.
 func GenerateContent(fm types.FrontMatter, body string) ([]byte, error) {
-	var buf bytes.Buffer
-
-	// ---
-	buf.WriteString("---\n")
+	return GenerateContentAs(FormatYAML, fm, body)
+}
 
-	// front matter
-	out, err := yaml.Marshal(fm)
-	if err != nil {
+// GenerateContentAs generates markdown content with front matter in the given format.
+func GenerateContentAs(format Format, fm types.FrontMatter, body string) ([]byte, error) {
+	if format == "" {
+		format = FormatYAML
+	}
+	doc := &Document{
+		Body:    body,
+		Newline: detectNewline([]byte(body)),
+		Format:  format,
+	}
+	if err := doc.SetFrontMatter(fm); err != nil {
 		return nil, err
 	}
-	buf.Write(out)
-
-	// ---
-	buf.WriteString("---\n\n")
-
-	// body
-	buf.WriteString(body)
-
-	return buf.Bytes(), nil
+	return doc.Bytes(), nil
 }
 
 // ParseContent parses markdown content and separates front matter and body.
+// Content without front matter yields an empty FrontMatter and the whole content as body.
 func ParseContent(content []byte) (types.FrontMatter, string, error) {
-	parts := bytes.SplitN(content, []byte("---\n"), 3)
-	if len(parts) < 3 {
-		return types.FrontMatter{}, "", fmt.Errorf("failed to parse front matter")
+	doc, err := Parse(content)
+	if err != nil {
+		return types.FrontMatter{}, "", err
 	}
 
 	var fm types.FrontMatter
-	if err := yaml.Unmarshal(parts[1], &fm); err != nil {
+	if err := doc.Decode(&fm); err != nil {
 		return types.FrontMatter{}, "", err
 	}
 
-	body := strings.TrimSpace(string(parts[2]))
+	return fm, doc.Body, nil
+}
+
+// Decode unmarshals the front matter into v.
+func (d *Document) Decode(v interface{}) error {
+	if d.node == nil {
+		return nil
+	}
+	if err := d.node.Decode(v); err != nil {
+		return d.wrapError(err)
+	}
+	return nil
+}
+
+// Bytes renders the document back into file content.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	if d.BOM {
+		buf.Write(utf8BOM)
+	}
+
+	if d.HasFrontMatter {
+		nl := d.Newline
+		if nl == "" {
+			nl = "\n"
+		}
+		fm := string(d.FrontMatter)
+		if nl != "\n" {
+			fm = strings.ReplaceAll(strings.ReplaceAll(fm, "\r\n", "\n"), "\n", nl)
+		}
+
+		// --- (+++ for TOML, none for JSON)
+		if delimiter := d.Format.delimiter(); delimiter != "" {
+			buf.WriteString(delimiter + nl)
+		}
+
+		// front matter
+		buf.WriteString(fm)
+
+		// ---
+		if delimiter := d.Format.delimiter(); delimiter != "" {
+			buf.WriteString(delimiter + nl)
+		}
+		if !d.compact {
+			buf.WriteString(nl)
+		}
+	}
+
+	// body
+	buf.WriteString(d.Body)
 
-	return fm, body, nil
+	return buf.Bytes()
 }
@@ -0,0 +1,201 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// utf8BOM is the byte order mark some editors put at the beginning of a file.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Document is a markdown file split into its front matter and body.
+type Document struct {
+	// FrontMatter is the raw YAML between the delimiters.
+	FrontMatter []byte
+	// Body is the content after the front matter, kept byte-for-byte.
+	Body string
+	// HasFrontMatter reports whether the file had a front matter block.
+	HasFrontMatter bool
+	// BOM reports whether the file started with a UTF-8 byte order mark.
+	BOM bool
+	// Newline is the line ending used by the delimiters ("\n" or "\r\n").
+	Newline string
+	// Format is the front matter format (YAML, TOML or JSON).
+	Format Format
+
+	// line is the line number where the front matter YAML starts.
+	line int
+	// compact reports that no blank line separated the front matter and the body.
+	compact bool
+	// node is the parsed front matter, used to edit it without losing unknown keys.
+	node *yaml.Node
+}
+
+// ParseError is an error in the front matter with its position in the file.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("front matter: line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("front matter: %s", e.Msg)
+}
+
+// Parse splits content into front matter and body.
+// YAML (---), TOML (+++) and JSON ({ ... }) front matter are detected.
+// A file without an opening delimiter, whose opening delimiter is never
+// closed, or that starts with a brace but not with a complete JSON object,
+// is treated as a body without front matter. The body keeps its
+// whitespace and line endings; only the single blank line that separates it
+// from the front matter is removed.
+func Parse(content []byte) (*Document, error) {
+	doc := &Document{Newline: "\n", Format: FormatYAML}
+	if bytes.HasPrefix(content, utf8BOM) {
+		doc.BOM = true
+		content = content[len(utf8BOM):]
+	}
+
+	if bytes.HasPrefix(content, []byte("{")) {
+		return parseJSON(doc, content)
+	}
+
+	first, rest, ok := cutLine(content)
+	if ok {
+		switch string(trimNewline(first)) {
+		case yamlDelimiter:
+			doc.Format = FormatYAML
+		case tomlDelimiter:
+			doc.Format = FormatTOML
+		default:
+			ok = false
+		}
+	}
+	if !ok {
+		doc.Body = string(content)
+		doc.Newline = detectNewline(content)
+		return doc, nil
+	}
+	delimiter := doc.Format.delimiter()
+	if doc.Newline = string(first[len(delimiter):]); doc.Newline == "" {
+		doc.Newline = "\n"
+	}
+
+	for remaining := rest; ; {
+		line, next, ok := cutLine(remaining)
+		if !ok {
+			// Unclosed: the leading --- is a horizontal rule.
+			doc.Format = FormatYAML
+			doc.Body = string(content)
+			return doc, nil
+		}
+		if s := string(trimNewline(line)); s == delimiter || (doc.Format == FormatYAML && s == "...") {
+			doc.HasFrontMatter = true
+			doc.FrontMatter = rest[:len(rest)-len(remaining)]
+			doc.line = 2
+			doc.setBody(next)
+			if err := doc.parseNode(); err != nil {
+				return nil, err
+			}
+			return doc, nil
+		}
+		remaining = next
+	}
+}
+
+// parseJSON parses a JSON object at the beginning of content as front matter.
+func parseJSON(doc *Document, content []byte) (*Document, error) {
+	doc.Format = FormatJSON
+	doc.Newline = detectNewline(content)
+
+	// Only a complete JSON object is front matter. A body that merely starts
+	// with a brace, such as a Hugo shortcode ({{< note >}}), has none.
+	var raw map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(content))
+	if err := dec.Decode(&raw); err != nil {
+		doc.Format = FormatYAML
+		doc.Body = string(content)
+		return doc, nil
+	}
+
+	// The object must end its line; the rest of the line ending belongs to the front matter.
+	end := int(dec.InputOffset())
+	line, next, _ := cutLine(content[end:])
+	if len(bytes.TrimSpace(line)) > 0 {
+		doc.Format = FormatYAML
+		doc.Body = string(content)
+		return doc, nil
+	}
+	doc.HasFrontMatter = true
+	doc.FrontMatter = content[:end+len(line)]
+	doc.line = 1
+	doc.setBody(next)
+	if err := doc.parseNode(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// setBody stores the content after the front matter as the body.
+func (d *Document) setBody(rest []byte) {
+	body := trimSeparator(rest)
+	d.compact = len(body) == len(rest)
+	d.Body = string(body)
+}
+
+// cutLine returns the first line of b including its line ending.
+// A final line without a line ending is returned as well; ok is false only for empty input.
+func cutLine(b []byte) (line, rest []byte, ok bool) {
+	if len(b) == 0 {
+		return nil, nil, false
+	}
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i+1], b[i+1:], true
+	}
+	return b, nil, true
+}
+
+func trimNewline(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r"))
+}
+
+// trimSeparator removes the one blank line written between the front matter and the body.
+func trimSeparator(body []byte) []byte {
+	if bytes.HasPrefix(body, []byte("\r\n")) {
+		return body[2:]
+	}
+	if bytes.HasPrefix(body, []byte("\n")) {
+		return body[1:]
+	}
+	return body
+}
+
+func detectNewline(b []byte) string {
+	if i := bytes.IndexByte(b, '\n'); i > 0 && b[i-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+var linePattern = regexp.MustCompile(`line (\d+): (.*)`)
+
+// wrapError converts a front matter error into a ParseError with a line number relative to the file.
+func (d *Document) wrapError(err error) error {
+	m := linePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return &ParseError{Line: d.line, Msg: err.Error()}
+	}
+	line, _ := strconv.Atoi(m[1])
+	if line == 0 {
+		line = 1
+	}
+	return &ParseError{Line: d.line + line - 1, Msg: m[2]}
+}
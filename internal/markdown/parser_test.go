@@ -0,0 +1,151 @@
+package markdown
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestParseContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantFm   types.FrontMatter
+		wantBody string
+		wantErr  bool
+		errLine  int
+	}{
+		{
+			name:     "正常系：Front Matterと本文を分割できる",
+			content:  "---\ntitle: テスト\ncategory: 開発\ntags:\n- go\nwip: true\n---\n\n本文\n",
+			wantFm:   types.FrontMatter{Title: "テスト", Category: "開発", Tags: []string{"go"}, Wip: true},
+			wantBody: "本文\n",
+		},
+		{
+			name:     "正常系：CRLFのファイル",
+			content:  "---\r\ntitle: テスト\r\n---\r\n\r\n本文\r\n2行目\r\n",
+			wantFm:   types.FrontMatter{Title: "テスト"},
+			wantBody: "本文\r\n2行目\r\n",
+		},
+		{
+			name:     "正常系：BOM付きのファイル",
+			content:  "\xEF\xBB\xBF---\ntitle: テスト\n---\n\n本文",
+			wantFm:   types.FrontMatter{Title: "テスト"},
+			wantBody: "本文",
+		},
+		{
+			name:     "正常系：本文中の --- は区切りとして扱わない",
+			content:  "---\ntitle: テスト\n---\n\n---\n\n段落\n---\n",
+			wantFm:   types.FrontMatter{Title: "テスト"},
+			wantBody: "---\n\n段落\n---\n",
+		},
+		{
+			name:     "正常系：本文の前後の空白を保持する",
+			content:  "---\ntitle: テスト\n---\n\n\n  インデント  \n\n\n",
+			wantFm:   types.FrontMatter{Title: "テスト"},
+			wantBody: "\n  インデント  \n\n\n",
+		},
+		{
+			name:     "エッジケース：Front Matterが無い",
+			content:  "# 見出し\n\n本文\n",
+			wantBody: "# 見出し\n\n本文\n",
+		},
+		{
+			name:     "エッジケース：空のFront Matter",
+			content:  "---\n---\n本文\n",
+			wantBody: "本文\n",
+		},
+		{
+			name:     "エッジケース：閉じられていない --- は水平線として扱う",
+			content:  "---\n\n本文\n",
+			wantBody: "---\n\n本文\n",
+		},
+		{
+			name:    "異常系：不正なYAMLは行番号付きのエラー",
+			content: "---\ntitle: テスト\ntags: [go\n---\n\n本文\n",
+			wantErr: true,
+			errLine: 3,
+		},
+		{
+			name:    "異常系：複数行の値の後にある不正なYAMLの行番号",
+			content: "---\ntitle: テスト\ntags: [go,\n  cli]\ncategory: a: b\n---\n\n本文\n",
+			wantErr: true,
+			errLine: 5,
+		},
+		{
+			name:    "異常系：インデントにタブを使ったYAMLの行番号",
+			content: "---\ntitle: テスト\nwip: true\n\tnumber: 1\n---\n\n本文\n",
+			wantErr: true,
+			errLine: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := ParseContent([]byte(tt.content))
+			if tt.wantErr {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("ParseError が返されていません: %v", err)
+				}
+				if parseErr.Line != tt.errLine {
+					t.Errorf("エラー行 = %d, want %d (%v)", parseErr.Line, tt.errLine, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseContent() error = %v", err)
+			}
+			if fm.Title != tt.wantFm.Title || fm.Category != tt.wantFm.Category || fm.Wip != tt.wantFm.Wip || len(fm.Tags) != len(tt.wantFm.Tags) {
+				t.Errorf("FrontMatter = %+v, want %+v", fm, tt.wantFm)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestDocument_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "正常系：LF", content: "---\ntitle: テスト\n---\n\n本文\n\n"},
+		{name: "正常系：CRLFとBOM", content: "\xEF\xBB\xBF---\r\ntitle: テスト\r\n---\r\n\r\n本文\r\n"},
+		{name: "正常系：区切りの空行が無い", content: "---\ntitle: テスト\n---\n本文"},
+		{name: "エッジケース：Front Matterが無い", content: "本文のみ\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := string(doc.Bytes()); got != tt.content {
+				t.Errorf("Bytes() = %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
+
+func TestGenerateContent(t *testing.T) {
+	body := "本文\r\n\r\n末尾の空行\r\n\r\n"
+	content, err := GenerateContent(types.FrontMatter{Title: "テスト"}, body)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	fm, got, err := ParseContent(content)
+	if err != nil {
+		t.Fatalf("ParseContent() error = %v", err)
+	}
+	if fm.Title != "テスト" {
+		t.Errorf("Title = %q, want %q", fm.Title, "テスト")
+	}
+	if got != body {
+		t.Errorf("body = %q, want %q", got, body)
+	}
+}
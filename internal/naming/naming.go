@@ -0,0 +1,147 @@
+// Package naming 記事を保存するファイル名を生成する
+package naming
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultTemplate ファイル名テンプレートのデフォルト
+const DefaultTemplate = "{number}-{name}.md"
+
+// MaxNameBytes ファイル名に使うタイトル（カテゴリは階層ごと）の最大長（バイト数）
+const MaxNameBytes = 100
+
+// FileName テンプレートから記事のファイル名を生成
+// 使用できるプレースホルダー: {number} {name} {slug} {category}
+// タイトルとカテゴリはファイル名に使える形に変換し、{category} の / だけをディレクトリの区切りとして残す
+func FileName(tmpl string, number int, name, category string) string {
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+	r := strings.NewReplacer(
+		"{number}", strconv.Itoa(number),
+		"{name}", Sanitize(name),
+		"{slug}", Slug(name),
+		"{category}", CategoryDir(category),
+	)
+	// カテゴリが空の場合に残る余分な / を除く
+	return strings.TrimPrefix(path.Clean("/"+r.Replace(tmpl)), "/")
+}
+
+// Sanitize タイトルをファイル名に使える形にする
+// Unicode を NFC に正規化し、パスの区切りや Windows で使えない文字（\ / : * ? " < > |）と制御文字を - に置き換える
+// 隠しファイルにならないよう先頭・末尾の空白とドットを除き、MaxNameBytes を超える場合は文字の途中で切らずに切り詰める
+func Sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return '-'
+		}
+		return r
+	}, norm.NFC.String(name))
+	name = trim(Truncate(trim(name), MaxNameBytes))
+	if name == "" {
+		return "untitled"
+	}
+	if isReserved(name) {
+		name += "_"
+	}
+	return name
+}
+
+// Slug タイトルを空白を含まない形にする（{slug}）
+// Sanitize に加えて空白を - にして連続する - をまとめ、英字を小文字にする
+func Slug(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(Sanitize(name)), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-'
+	})
+	if len(fields) == 0 {
+		return "untitled"
+	}
+	return Truncate(strings.Join(fields, "-"), MaxNameBytes)
+}
+
+// Truncate UTF-8 の文字の途中で切らずに limit バイト以内に切り詰める
+func Truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	s = s[:limit]
+	for len(s) > 0 {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if r != utf8.RuneError || size > 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// Unique パスが使われていれば、拡張子の前に -2, -3, ... を付けた使われていないパスを返す
+func Unique(p string, taken func(path string) bool) string {
+	if !taken(p) {
+		return p
+	}
+	ext := filepath.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// NumberFromFileName ファイル名の先頭の「記事番号-」から記事番号を取得
+// ディレクトリ部分は無視する（docs/123-x.md → 123）
+func NumberFromFileName(path string) (int, bool) {
+	prefix, _, found := strings.Cut(filepath.Base(path), "-")
+	if !found {
+		return 0, false
+	}
+	number, err := strconv.Atoi(prefix)
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// CategoryDir カテゴリのディレクトリ（{category}）
+// 階層ごとにファイル名に使える形にして / でつなぐ
+func CategoryDir(category string) string {
+	var dirs []string
+	for _, dir := range strings.Split(category, "/") {
+		if strings.Trim(dir, " .") == "" {
+			continue
+		}
+		dirs = append(dirs, Sanitize(dir))
+	}
+	return strings.Join(dirs, "/")
+}
+
+// trim 先頭・末尾の空白とドットを除く（Windows は末尾のドット・空白を無視する）
+func trim(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.'
+	})
+}
+
+// isReserved Windows で予約されているファイル名か（CON, NUL, COM1 など）
+func isReserved(name string) bool {
+	base, _, _ := strings.Cut(strings.ToUpper(name), ".")
+	switch base {
+	case "CON", "PRN", "AUX", "NUL":
+		return true
+	}
+	if len(base) == 4 && (strings.HasPrefix(base, "COM") || strings.HasPrefix(base, "LPT")) {
+		return base[3] >= '1' && base[3] <= '9'
+	}
+	return false
+}
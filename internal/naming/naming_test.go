@@ -0,0 +1,145 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		number   int
+		title    string
+		category string
+		want     string
+	}{
+		{
+			name:   "正常系：テンプレート未指定なら記事番号-タイトル.md",
+			number: 123,
+			title:  "テスト記事",
+			want:   "123-テスト記事.md",
+		},
+		{
+			name:     "正常系：カテゴリをディレクトリにできる",
+			tmpl:     "{category}/{number}-{name}.md",
+			number:   1,
+			title:    "記事",
+			category: "開発/API",
+			want:     "開発/API/1-記事.md",
+		},
+		{
+			name:   "エッジケース：カテゴリが空の場合は先頭のスラッシュを除く",
+			tmpl:   "{category}/{name}.md",
+			number: 1,
+			title:  "記事",
+			want:   "記事.md",
+		},
+		{
+			name:   "正常系：タイトルの / はディレクトリにしない",
+			number: 1,
+			title:  "入門/応用: Q&A?",
+			want:   "1-入門-応用- Q&A-.md",
+		},
+		{
+			name:   "正常系：slug は空白を - にして小文字にする",
+			tmpl:   "{number}-{slug}.md",
+			number: 1,
+			title:  "Go  Modules 入門 / 基本",
+			want:   "1-go-modules-入門-基本.md",
+		},
+		{
+			name:     "正常系：カテゴリの階層もファイル名に使える形にする",
+			tmpl:     "{category}/{name}.md",
+			number:   1,
+			title:    "記事",
+			category: "開発:2025/../API",
+			want:     "開発-2025/API/記事.md",
+		},
+		{
+			name:   "エッジケース：隠しファイルにしない",
+			number: 1,
+			tmpl:   "{name}.md",
+			title:  ".env の設定",
+			want:   "env の設定.md",
+		},
+		{
+			name:   "エッジケース：Windows の予約名を避ける",
+			tmpl:   "{name}.md",
+			number: 1,
+			title:  "con",
+			want:   "con_.md",
+		},
+		{
+			name:   "エッジケース：濁点が結合文字でもNFCに正規化する",
+			number: 1,
+			title:  "ハ\u309aン",
+			want:   "1-パン.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FileName(tt.tmpl, tt.number, tt.title, tt.category); got != tt.want {
+				t.Errorf("FileName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberFromFileName(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		want   int
+		wantOK bool
+	}{
+		{name: "正常系：記事番号-タイトル.md", path: "123-テスト.md", want: 123, wantOK: true},
+		{name: "正常系：サブディレクトリ内のファイル", path: "docs/45-記事.md", want: 45, wantOK: true},
+		{name: "異常系：記事番号が無い", path: "メモ.md", wantOK: false},
+		{name: "異常系：先頭が数値でない", path: "draft-テスト.md", wantOK: false},
+		{name: "エッジケース：ディレクトリ名の数値は使わない", path: "2024-05/メモ.md", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NumberFromFileName(tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NumberFromFileName(%q) = %d, %v, want %d, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSanitize_Truncate(t *testing.T) {
+	title := strings.Repeat("あ", 40) // 120バイト
+	got := Sanitize(title)
+	if len(got) > MaxNameBytes || !utf8.ValidString(got) {
+		t.Errorf("Sanitize() = %q（%dバイト）, want %dバイト以内の有効なUTF-8", got, len(got), MaxNameBytes)
+	}
+	if got != strings.Repeat("あ", 33) {
+		t.Errorf("Sanitize() = %q, want 33文字", got)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"docs/設計.md": true, "docs/設計-2.md": true}
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "正常系：使われていなければそのまま", path: "docs/手順.md", want: "docs/手順.md"},
+		{name: "正常系：使われていれば番号を付ける", path: "docs/設計.md", want: "docs/設計-3.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unique(tt.path, func(path string) bool { return taken[path] })
+			if got != tt.want {
+				t.Errorf("Unique() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
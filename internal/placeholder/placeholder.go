@@ -0,0 +1,87 @@
+package placeholder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// pattern %{name} 形式のプレースホルダー
+var pattern = regexp.MustCompile(`%\{([A-Za-z_]+)\}`)
+
+var (
+	weekdaysJa   = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+	weekdaysLong = [...]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"}
+)
+
+// dateValues 日時のプレースホルダー
+var dateValues = map[string]func(t time.Time) string{
+	"Year":          func(t time.Time) string { return t.Format("2006") },
+	"year":          func(t time.Time) string { return t.Format("06") },
+	"month":         func(t time.Time) string { return t.Format("01") },
+	"day":           func(t time.Time) string { return t.Format("02") },
+	"Hour":          func(t time.Time) string { return t.Format("15") },
+	"min":           func(t time.Time) string { return t.Format("04") },
+	"sec":           func(t time.Time) string { return t.Format("05") },
+	"week_day":      func(t time.Time) string { return weekdaysJa[t.Weekday()] },
+	"week_day_long": func(t time.Time) string { return weekdaysLong[t.Weekday()] },
+	"week_day_en":   func(t time.Time) string { return t.Format("Mon") },
+}
+
+// userValues ユーザー情報のプレースホルダー
+var userValues = map[string]func(u *types.User) string{
+	"me":   func(u *types.User) string { return u.ScreenName },
+	"name": func(u *types.User) string { return u.Name },
+}
+
+// Expander esa.ioのテンプレートと同じプレースホルダーを展開する
+type Expander struct {
+	// Now 日時のプレースホルダーに使う時刻
+	Now time.Time
+	// User 現在のユーザーを返す（%{me}・%{name} を含む場合のみ呼び出す）
+	User func() (*types.User, error)
+
+	user *types.User
+}
+
+// NeedsUser ユーザー情報が必要なプレースホルダーを含むか
+func NeedsUser(s string) bool {
+	for _, m := range pattern.FindAllStringSubmatch(s, -1) {
+		if _, ok := userValues[m[1]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand s 中のプレースホルダーを展開する
+// 未知のプレースホルダーはそのまま残す
+func (e *Expander) Expand(s string) (string, error) {
+	if !strings.Contains(s, "%{") {
+		return s, nil
+	}
+	if NeedsUser(s) && e.user == nil {
+		if e.User == nil {
+			return s, fmt.Errorf("ユーザー情報を取得できないため %%{me}・%%{name} を展開できません")
+		}
+		user, err := e.User()
+		if err != nil {
+			return s, fmt.Errorf("ユーザー情報の取得に失敗しました: %v", err)
+		}
+		e.user = user
+	}
+
+	return pattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := pattern.FindStringSubmatch(match)[1]
+		if f, ok := dateValues[name]; ok {
+			return f(e.Now)
+		}
+		if f, ok := userValues[name]; ok {
+			return f(e.user)
+		}
+		return match
+	}), nil
+}
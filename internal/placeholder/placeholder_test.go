@@ -0,0 +1,80 @@
+package placeholder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestExpander_Expand(t *testing.T) {
+	// 2025-01-05 は日曜日
+	now := time.Date(2025, 1, 5, 9, 3, 7, 0, time.Local)
+	user := &types.User{Name: "山田 太郎", ScreenName: "yamada"}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"正常系：日報のカテゴリ", "日報/%{Year}/%{month}/%{day}", "日報/2025/01/05", false},
+		{"正常系：2桁の年と時刻", "%{year}%{month}%{day}-%{Hour}%{min}%{sec}", "250105-090307", false},
+		{"正常系：日本語の曜日", "%{month}/%{day}(%{week_day})", "01/05(日)", false},
+		{"正常系：日本語の曜日（長い形式）", "%{week_day_long}の定例", "日曜日の定例", false},
+		{"正常系：英語の曜日", "%{week_day_en}", "Sun", false},
+		{"正常系：ユーザー名", "日報/%{me}/%{Year}", "日報/yamada/2025", false},
+		{"正常系：ユーザーの名前", "%{name}のメモ", "山田 太郎のメモ", false},
+		{"エッジケース：プレースホルダーなし", "開発/設計", "開発/設計", false},
+		{"エッジケース：未知のプレースホルダーはそのまま", "%{unknown}/%{Year}", "%{unknown}/2025", false},
+		{"エッジケース：閉じていない", "%{Year", "%{Year", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Expander{Now: now, User: func() (*types.User, error) { return user, nil }}
+			got, err := e.Expand(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpander_UserFetchedOnce(t *testing.T) {
+	calls := 0
+	e := &Expander{Now: time.Now(), User: func() (*types.User, error) {
+		calls++
+		return &types.User{ScreenName: "yamada"}, nil
+	}}
+
+	for _, s := range []string{"%{Year}", "%{me}", "%{me}/%{name}"} {
+		if _, err := e.Expand(s); err != nil {
+			t.Fatalf("Expand(%q) error = %v", s, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("User() called %d times, want 1", calls)
+	}
+}
+
+func TestExpander_UserError(t *testing.T) {
+	e := &Expander{Now: time.Now(), User: func() (*types.User, error) {
+		return nil, errors.New("unauthorized")
+	}}
+
+	if _, err := e.Expand("%{me}"); err == nil {
+		t.Error("Expand() error = nil, want error")
+	}
+	// ユーザー情報を使わなければ取得に失敗しても展開できる
+	if _, err := e.Expand("%{Year}"); err != nil {
+		t.Errorf("Expand() error = %v", err)
+	}
+	if _, err := (&Expander{}).Expand("%{me}"); err == nil {
+		t.Error("Expand() without User error = nil, want error")
+	}
+}
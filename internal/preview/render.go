@@ -0,0 +1,99 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/pkg/types"
+	"github.com/yuin/goldmark"
+	emoji "github.com/yuin/goldmark-emoji"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Page プレビューする記事
+type Page struct {
+	Title    string
+	Category string
+	Tags     []string
+	Wip      bool
+	Body     template.HTML
+}
+
+// renderer GFM・絵文字・シンタックスハイライトに対応したMarkdownレンダラー
+var renderer = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		emoji.Emoji,
+		highlighting.NewHighlighting(highlighting.WithStyle("github")),
+	),
+	goldmark.WithParserOptions(
+		parser.WithASTTransformers(util.Prioritized(headingIDs{}, 100)),
+	),
+	goldmark.WithRendererOptions(
+		// esa.ioと同様に本文中のHTMLをそのまま表示する
+		html.WithUnsafe(),
+	),
+)
+
+// Render ファイルの内容をプレビュー用に変換する
+func Render(content []byte) (*Page, error) {
+	doc, err := markdown.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	var fm types.FrontMatter
+	if err := doc.Decode(&fm); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(doc.Body), &buf); err != nil {
+		return nil, fmt.Errorf("Markdownの変換に失敗しました: %v", err)
+	}
+	return &Page{
+		Title:    fm.Title,
+		Category: fm.Category,
+		Tags:     fm.Tags,
+		Wip:      fm.Wip,
+		Body:     template.HTML(buf.String()),
+	}, nil
+}
+
+// headingIDs esa.ioと同様に見出しの階層ごとの連番（1-0-0, 1-1-0, ...）をアンカーにする
+type headingIDs struct{}
+
+func (headingIDs) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	var counters [6]int
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		level := heading.Level
+		counters[level-1]++
+		for i := level; i < len(counters); i++ {
+			counters[i] = 0
+		}
+
+		depth := level
+		if depth < 3 {
+			depth = 3
+		}
+		parts := make([]string, depth)
+		for i := range parts {
+			parts[i] = strconv.Itoa(counters[i])
+		}
+		heading.SetAttributeString("id", []byte(strings.Join(parts, "-")))
+		return ast.WalkSkipChildren, nil
+	})
+}
@@ -0,0 +1,88 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		contains []string
+	}{
+		{
+			name:     "正常系：テーブル",
+			content:  "| a | b |\n|---|---|\n| 1 | 2 |\n",
+			contains: []string{"<table>", "<td>1</td>"},
+		},
+		{
+			name:     "正常系：タスクリスト",
+			content:  "- [x] done\n- [ ] todo\n",
+			contains: []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`},
+		},
+		{
+			name:     "正常系：絵文字",
+			content:  "OK :+1:\n",
+			contains: []string{"&#x1f44d;"},
+		},
+		{
+			name:     "正常系：コードのハイライト",
+			content:  "```go\nfunc main() {}\n```\n",
+			contains: []string{"<pre", "style=\""},
+		},
+		{
+			name:    "正常系：見出しのアンカー",
+			content: "# A\n## B\n### C\n## D\n# E\n",
+			contains: []string{
+				`<h1 id="1-0-0">A</h1>`,
+				`<h2 id="1-1-0">B</h2>`,
+				`<h3 id="1-1-1">C</h3>`,
+				`<h2 id="1-2-0">D</h2>`,
+				`<h1 id="2-0-0">E</h1>`,
+			},
+		},
+		{
+			name:     "正常系：HTMLはそのまま表示",
+			content:  "<div class=\"note\">memo</div>\n",
+			contains: []string{`<div class="note">memo</div>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Render([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(page.Body), want) {
+					t.Errorf("Render() = %s, want to contain %s", page.Body, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_FrontMatter(t *testing.T) {
+	content := "---\ntitle: テスト記事\ncategory: 開発/メモ\ntags:\n  - go\nwip: true\n---\n\n本文\n"
+	page, err := Render([]byte(content))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if page.Title != "テスト記事" || page.Category != "開発/メモ" || !page.Wip {
+		t.Errorf("Render() = %+v", page)
+	}
+	if len(page.Tags) != 1 || page.Tags[0] != "go" {
+		t.Errorf("Render() tags = %v", page.Tags)
+	}
+	if strings.Contains(string(page.Body), "title") {
+		t.Errorf("Front Matterは本文に含めない: %s", page.Body)
+	}
+}
+
+func TestRender_InvalidFrontMatter(t *testing.T) {
+	if _, err := Render([]byte("---\ntitle: [\n---\n")); err == nil {
+		t.Error("Render() error = nil, want error")
+	}
+}
@@ -0,0 +1,195 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// eventsPath ファイルの変更を通知するServer-Sent Eventsのパス
+const eventsPath = "/__esa-cli/events"
+
+// Server Markdownファイルをプレビューするサーバー
+// 画像などの相対パスを解決できるよう、記事は root からの相対パスで配信する
+type Server struct {
+	path     string
+	root     string
+	host     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+// New プレビューサーバーを作成する
+// root は静的ファイルを配信するディレクトリ。path が root の外にあれば path のディレクトリを使う
+// host は待ち受けているアドレス（127.0.0.1:8080 など）。DNSリバインディングで他のサイトから読まれないよう、
+// Host ヘッダーがこのアドレス（または同じポートの localhost）のリクエストだけを受け付ける
+func New(path, root, host string) (*Server, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(root, path); err != nil || strings.HasPrefix(rel, "..") {
+		root = filepath.Dir(path)
+	}
+	return &Server{
+		path:     path,
+		root:     root,
+		host:     host,
+		interval: 500 * time.Millisecond,
+		clients:  map[chan struct{}]struct{}{},
+	}, nil
+}
+
+// URLPath 記事を表示するURLのパス
+func (s *Server) URLPath() string {
+	rel, _ := filepath.Rel(s.root, s.path)
+	return "/" + filepath.ToSlash(rel)
+}
+
+// ServeHTTP 記事・変更通知・記事のディレクトリの静的ファイルを配信する
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedHost(r.Host) {
+		http.Error(w, "invalid host", http.StatusForbidden)
+		return
+	}
+	switch r.URL.Path {
+	case "/":
+		http.Redirect(w, r, (&url.URL{Path: s.URLPath()}).EscapedPath(), http.StatusFound)
+	case s.URLPath():
+		s.servePage(w)
+	case eventsPath:
+		s.serveEvents(w, r)
+	default:
+		// .esa-cli/（同期状態・ベース）や .trash などの隠しファイルは配信しない
+		if hiddenPath(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
+		http.FileServer(http.Dir(s.root)).ServeHTTP(w, r)
+	}
+}
+
+// allowedHost 待ち受けているアドレス宛てのリクエストか
+func (s *Server) allowedHost(host string) bool {
+	if host == s.host {
+		return true
+	}
+	_, port, err := net.SplitHostPort(s.host)
+	return err == nil && host == net.JoinHostPort("localhost", port)
+}
+
+// hiddenPath . で始まる要素を含むパスか
+func hiddenPath(path string) bool {
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) servePage(w http.ResponseWriter) {
+	data := struct {
+		Page   *Page
+		Err    error
+		Events string
+	}{Events: eventsPath}
+
+	content, err := os.ReadFile(s.path)
+	if err == nil {
+		data.Page, err = Render(content)
+	}
+	// 書きかけで解析できない場合もエラーを表示して変更を待つ
+	data.Err = err
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// Watch ファイルの変更を監視し、接続中のブラウザに再読み込みを通知する
+// ctx がキャンセルされるまで戻らない
+func (s *Server) Watch(ctx context.Context) {
+	last := s.stat()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if current := s.stat(); current != last {
+				last = current
+				s.notify()
+			}
+		}
+	}
+}
+
+// stat 変更検知に使うファイルの更新日時とサイズ
+func (s *Server) stat() string {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+}
+
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "docs", "1-テスト.md")
+	if err := os.WriteFile(path, []byte("---\ntitle: テスト\n---\n\n![img](../assets/a.png)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "a.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".esa-cli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".esa-cli", "state.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server, ts := startServer(t, path, root)
+	if got := server.URLPath(); got != "/docs/1-テスト.md" {
+		t.Errorf("URLPath() = %q", got)
+	}
+	_, port, _ := net.SplitHostPort(ts.Listener.Addr().String())
+
+	tests := []struct {
+		name       string
+		path       string
+		host       string // 空なら待ち受けているアドレス
+		wantStatus int
+		contains   string
+	}{
+		{"正常系：記事を表示", "/docs/1-%E3%83%86%E3%82%B9%E3%83%88.md", "", http.StatusOK, "<h1>テスト</h1>"},
+		{"正常系：ルートは記事にリダイレクト", "/", "", http.StatusOK, "<h1>テスト</h1>"},
+		{"正常系：相対パスの画像を配信", "/assets/a.png", "", http.StatusOK, "png"},
+		{"正常系：同じポートの localhost も受け付ける", "/assets/a.png", "localhost:" + port, http.StatusOK, "png"},
+		{"異常系：隠しディレクトリは配信しない", "/.esa-cli/state.json", "", http.StatusNotFound, ""},
+		{"異常系：待ち受けているアドレス以外の Host は拒否する", "/assets/a.png", "attacker.example:" + port, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus || !strings.Contains(string(body), tt.contains) {
+				t.Errorf("GET %s = %d %s, want %d and to contain %s", tt.path, resp.StatusCode, body, tt.wantStatus, tt.contains)
+			}
+		})
+	}
+}
+
+// startServer 待ち受けているアドレスを渡してプレビューサーバーを起動する
+func startServer(t *testing.T, path, root string) (*Server, *httptest.Server) {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(nil)
+	server, err := New(path, root, ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ts.Config.Handler = server
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+func TestServer_RootOutsideFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.md")
+	if err := os.WriteFile(path, []byte("body\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, err := New(path, t.TempDir(), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := server.URLPath(); got != "/a.md" {
+		t.Errorf("URLPath() = %q, want /a.md", got)
+	}
+}
+
+func TestServer_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.md")
+	if err := os.WriteFile(path, []byte("before\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server, ts := startServer(t, path, dir)
+	server.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Watch(ctx)
+
+	resp, err := http.Get(ts.URL + eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("after the change\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(chan string)
+	go func() {
+		line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		lines <- line
+	}()
+	select {
+	case line := <-lines:
+		if line != "data: reload\n" {
+			t.Errorf("event = %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ファイルの変更が通知されない")
+	}
+}
@@ -0,0 +1,47 @@
+package preview
+
+import "html/template"
+
+// pageTemplate プレビュー画面のHTML
+// ファイルが変更されるとServer-Sent Eventsで通知を受けて再読み込みする
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Page}}{{.Page.Title}}{{else}}プレビュー{{end}} - esa-cli preview</title>
+<style>
+body { max-width: 880px; margin: 0 auto; padding: 24px; font-family: -apple-system, BlinkMacSystemFont, "Hiragino Sans", "Hiragino Kaku Gothic ProN", Meiryo, sans-serif; line-height: 1.7; color: #333; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 24px; padding-bottom: 12px; }
+header .category { color: #888; font-size: 14px; }
+header h1 { margin: 4px 0; font-size: 28px; }
+header .wip { background: #c3c3c3; color: #fff; border-radius: 3px; padding: 0 6px; font-size: 14px; vertical-align: middle; margin-right: 6px; }
+header .tag { display: inline-block; background: #f0f5f5; color: #0a9b94; border-radius: 3px; padding: 0 6px; margin-right: 4px; font-size: 13px; }
+.error { background: #fdecea; color: #b71c1c; padding: 12px; border-radius: 4px; white-space: pre-wrap; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 6px 12px; }
+pre { padding: 12px; overflow: auto; border-radius: 4px; background: #f8f8f8; }
+code { font-family: Menlo, Consolas, monospace; font-size: 90%; }
+img { max-width: 100%; }
+blockquote { margin-left: 0; padding-left: 16px; border-left: 4px solid #ddd; color: #777; }
+li.task-list-item, ul.contains-task-list { list-style: none; }
+</style>
+</head>
+<body>
+{{if .Err}}<div class="error">{{.Err}}</div>{{end}}
+{{with .Page}}
+<header>
+{{if .Category}}<div class="category">{{.Category}}</div>{{end}}
+<h1>{{if .Wip}}<span class="wip">WIP</span>{{end}}{{.Title}}</h1>
+{{range .Tags}}<span class="tag">#{{.}}</span>{{end}}
+</header>
+<article>
+{{.Body}}
+</article>
+{{end}}
+<script>
+new EventSource("{{.Events}}").onmessage = function () { location.reload(); };
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,132 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/placeholder"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// Category esa.ioのテンプレート記事を置くカテゴリ
+const Category = "Templates"
+
+// Lister テンプレートの取得に使うAPI
+type Lister interface {
+	ListPosts(ctx context.Context, options *api.ListPostsOptions) ([]*types.Post, error)
+	FetchPost(ctx context.Context, postNum int) (*types.Post, error)
+}
+
+// IsTemplate Templatesカテゴリの記事か
+func IsTemplate(post *types.Post) bool {
+	return post.Category == Category || strings.HasPrefix(post.Category, Category+"/")
+}
+
+// Target テンプレートから作成される記事のカテゴリとタイトル
+// テンプレート記事のフルネームから Templates/ を除いたものが作成される記事のフルネームになる
+func Target(post *types.Post) (category, name string) {
+	fullName := strings.TrimPrefix(post.Category, Category)
+	fullName = strings.TrimPrefix(fullName+"/"+post.Name, "/")
+	if i := strings.LastIndex(fullName, "/"); i >= 0 {
+		return fullName[:i], fullName[i+1:]
+	}
+	return "", fullName
+}
+
+// List テンプレートの一覧を取得する（作成される記事のフルネーム順）
+func List(ctx context.Context, client Lister) ([]*types.Post, error) {
+	var all []*types.Post
+	const perPage = 100
+	for page := 1; ; page++ {
+		posts, err := client.ListPosts(ctx, &api.ListPostsOptions{
+			Query: "in:" + Category,
+			Limit: perPage,
+			Page:  page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, post := range posts {
+			if IsTemplate(post) {
+				all = append(all, post)
+			}
+		}
+		if len(posts) < perPage {
+			break
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return fullName(all[i]) < fullName(all[j])
+	})
+	return all, nil
+}
+
+func fullName(post *types.Post) string {
+	category, name := Target(post)
+	if category == "" {
+		return name
+	}
+	return category + "/" + name
+}
+
+// Find 記事番号またはタイトルでテンプレートを探す
+// タイトルはテンプレート記事のタイトル、または作成される記事のフルネームと比較する
+func Find(ctx context.Context, client Lister, ref string) (*types.Post, error) {
+	if number, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		post, err := client.FetchPost(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if !IsTemplate(post) {
+			return nil, fmt.Errorf("記事 #%d は %s カテゴリの記事ではありません", number, Category)
+		}
+		return post, nil
+	}
+
+	posts, err := List(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var found []*types.Post
+	for _, post := range posts {
+		if post.Name == ref || fullName(post) == ref {
+			found = append(found, post)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("テンプレート '%s' が見つかりません", ref)
+	case 1:
+		return found[0], nil
+	default:
+		numbers := make([]string, len(found))
+		for i, post := range found {
+			numbers[i] = "#" + strconv.Itoa(post.Number)
+		}
+		return nil, fmt.Errorf("テンプレート '%s' が複数あります（%s）。記事番号で指定してください", ref, strings.Join(numbers, ", "))
+	}
+}
+
+// Materialize テンプレートからローカルの下書きの内容を作成する
+// タイトル・カテゴリ・本文のプレースホルダーは展開する
+func Materialize(post *types.Post, expander *placeholder.Expander) (types.FrontMatter, string, error) {
+	category, name := Target(post)
+	fm := types.FrontMatter{
+		Category: category,
+		Title:    name,
+		Tags:     post.Tags,
+	}
+	body := post.BodyMd
+	for _, v := range []*string{&fm.Category, &fm.Title, &body} {
+		expanded, err := expander.Expand(*v)
+		if err != nil {
+			return types.FrontMatter{}, "", err
+		}
+		*v = expanded
+	}
+	return fm, body, nil
+}
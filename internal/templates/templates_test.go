@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/api"
+	"github.com/shellme/esa-cli/internal/placeholder"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+type fakeLister struct {
+	posts []*types.Post
+}
+
+func (f *fakeLister) ListPosts(ctx context.Context, options *api.ListPostsOptions) ([]*types.Post, error) {
+	if options.Page > 1 {
+		return nil, nil
+	}
+	return f.posts, nil
+}
+
+func (f *fakeLister) FetchPost(ctx context.Context, postNum int) (*types.Post, error) {
+	for _, post := range f.posts {
+		if post.Number == postNum {
+			return post, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+var testPosts = []*types.Post{
+	{Number: 1, Name: "%{Year}-%{month}-%{day}", Category: "Templates/日報/%{me}", Tags: []string{"日報"}, BodyMd: "# %{me} の日報\n"},
+	{Number: 2, Name: "議事録", Category: "Templates", BodyMd: "## 参加者\n"},
+	{Number: 3, Name: "議事録", Category: "Templates/定例"},
+	{Number: 4, Name: "普通の記事", Category: "開発"},
+}
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		post         *types.Post
+		wantCategory string
+		wantName     string
+	}{
+		{"正常系：サブカテゴリのテンプレート", testPosts[0], "日報/%{me}", "%{Year}-%{month}-%{day}"},
+		{"正常系：Templates直下のテンプレート", testPosts[1], "", "議事録"},
+		{"正常系：1階層のサブカテゴリ", testPosts[2], "定例", "議事録"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, name := Target(tt.post)
+			if category != tt.wantCategory || name != tt.wantName {
+				t.Errorf("Target() = (%q, %q), want (%q, %q)", category, name, tt.wantCategory, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	posts, err := List(context.Background(), &fakeLister{posts: testPosts})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var numbers []int
+	for _, post := range posts {
+		numbers = append(numbers, post.Number)
+	}
+	// Templatesカテゴリ以外の記事は含めず、作成される記事のフルネーム順に並べる
+	want := []int{3, 1, 2}
+	if len(numbers) != len(want) {
+		t.Fatalf("List() = %v, want %v", numbers, want)
+	}
+	for i := range want {
+		if numbers[i] != want[i] {
+			t.Fatalf("List() = %v, want %v", numbers, want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	client := &fakeLister{posts: testPosts}
+	tests := []struct {
+		name    string
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{"正常系：記事番号", "1", 1, false},
+		{"正常系：#付きの記事番号", "#2", 2, false},
+		{"正常系：作成される記事のフルネーム", "定例/議事録", 3, false},
+		{"正常系：テンプレートのタイトル", "%{Year}-%{month}-%{day}", 1, false},
+		{"異常系：同じタイトルが複数", "議事録", 0, true},
+		{"異常系：見つからない", "週報", 0, true},
+		{"異常系：テンプレートではない記事", "4", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post, err := Find(context.Background(), client, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Find() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && post.Number != tt.want {
+				t.Errorf("Find() = #%d, want #%d", post.Number, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	expander := &placeholder.Expander{
+		Now:  time.Date(2025, 1, 5, 0, 0, 0, 0, time.Local),
+		User: func() (*types.User, error) { return &types.User{ScreenName: "yamada"}, nil },
+	}
+	fm, body, err := Materialize(testPosts[0], expander)
+	if err != nil {
+		t.Fatalf("Materialize() error = %v", err)
+	}
+	if fm.Category != "日報/yamada" || fm.Title != "2025-01-05" {
+		t.Errorf("Materialize() = %+v", fm)
+	}
+	if len(fm.Tags) != 1 || fm.Tags[0] != "日報" {
+		t.Errorf("Materialize() tags = %v", fm.Tags)
+	}
+	if body != "# yamada の日報\n" {
+		t.Errorf("Materialize() body = %q", body)
+	}
+}
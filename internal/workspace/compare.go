@@ -0,0 +1,212 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// Change ローカルとリモートの差分の種類
+type Change string
+
+const (
+	// Unchanged 最後に同期してから変更なし
+	Unchanged Change = "unchanged"
+	// LocalModified ローカルだけ変更あり
+	LocalModified Change = "local_modified"
+	// RemoteModified リモートだけ変更あり
+	RemoteModified Change = "remote_modified"
+	// Conflict ローカルとリモートの両方に変更あり
+	Conflict Change = "conflict"
+	// NewRemote ワークスペースに無いリモートの記事
+	NewRemote Change = "new_remote"
+	// NewLocal 記事番号の無いローカルファイル（まだ投稿していない下書き）
+	NewLocal Change = "new_local"
+	// RemoteDeleted 同期済みだがリモートに見つからない記事
+	RemoteDeleted Change = "remote_deleted"
+	// LocalDeleted 同期済みだがローカルファイルが見つからない記事
+	LocalDeleted Change = "local_deleted"
+	// Untracked 記事番号はあるが同期状態に記録されていないローカルファイル
+	Untracked Change = "untracked"
+)
+
+// LocalFile ワークスペース内のMarkdownファイル
+type LocalFile struct {
+	Path   string
+	Number int
+	Hash   string
+}
+
+// Item 記事またはファイルごとの差分
+type Item struct {
+	Change Change
+	Number int
+	// Path ローカルファイルの絶対パス（ファイルが無ければ空）
+	Path string
+	// Post リモートの記事（見つからなければ nil）
+	Post *types.Post
+	// Entry 同期状態（記録が無ければ nil）
+	Entry *Entry
+	// Duplicate 同じ記事番号のファイルが複数ある（Change は Conflict）
+	Duplicate bool
+}
+
+// ScanFiles ワークスペース内のMarkdownファイルを列挙する
+// 隠しディレクトリと、ignored が true を返すファイルは対象外
+func ScanFiles(root string, ignored func(path string) bool) ([]LocalFile, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	var files []LocalFile
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") || (ignored != nil && ignored(path)) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		number, _ := readNumber(path)
+		files = append(files, LocalFile{Path: path, Number: number, Hash: Hash(content)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Compare 同期状態をもとにローカルファイルとリモートの記事を比較する
+// remote に含まれない同期済みの記事は RemoteDeleted になるため、同期済みの記事をすべて渡すこと
+func (s *State) Compare(local []LocalFile, remote []*types.Post) []Item {
+	return s.CompareIn("", local, remote)
+}
+
+// CompareIn カテゴリ配下の記事として同期したものだけを対象に比較する
+// remote にはカテゴリ配下の記事をすべて渡す。カテゴリ外で同期した記事は remote に無ければ比較しない
+// 同じ記事番号のファイルが複数あれば、どれをアップロードするか決められないため Conflict にする
+func (s *State) CompareIn(category string, local []LocalFile, remote []*types.Post) []Item {
+	localByNumber := map[int]LocalFile{}
+	duplicates := map[int][]LocalFile{}
+	var items []Item
+	for _, f := range local {
+		if f.Number == 0 {
+			items = append(items, Item{Change: NewLocal, Path: f.Path})
+			continue
+		}
+		if first, ok := localByNumber[f.Number]; ok {
+			if len(duplicates[f.Number]) == 0 {
+				duplicates[f.Number] = []LocalFile{first}
+			}
+			duplicates[f.Number] = append(duplicates[f.Number], f)
+			continue
+		}
+		localByNumber[f.Number] = f
+	}
+	remoteByNumber := map[int]*types.Post{}
+	for _, post := range remote {
+		remoteByNumber[post.Number] = post
+	}
+
+	for number, files := range duplicates {
+		e, _ := s.Entry(number)
+		for _, f := range files {
+			items = append(items, Item{Change: Conflict, Number: number, Path: f.Path, Post: remoteByNumber[number], Entry: e, Duplicate: true})
+		}
+	}
+
+	for _, e := range s.Entries() {
+		if _, ok := duplicates[e.Number]; ok {
+			continue
+		}
+		if !e.InCategory(category) && remoteByNumber[e.Number] == nil {
+			continue
+		}
+		f, hasLocal := localByNumber[e.Number]
+		post := remoteByNumber[e.Number]
+		item := Item{Number: e.Number, Path: f.Path, Post: post, Entry: e}
+		switch {
+		case !hasLocal:
+			item.Change = LocalDeleted
+		case post == nil:
+			item.Change = RemoteDeleted
+		default:
+			localChanged := f.Hash != e.Hash
+			remoteChanged := remoteMoved(e, post)
+			switch {
+			case e.Conflict && !localChanged:
+				// 競合マーカーを書き込んでから編集されていない
+				item.Change = Conflict
+			case localChanged && remoteChanged:
+				item.Change = Conflict
+			case localChanged:
+				item.Change = LocalModified
+			case remoteChanged:
+				item.Change = RemoteModified
+			default:
+				item.Change = Unchanged
+			}
+		}
+		items = append(items, item)
+	}
+
+	for number, f := range localByNumber {
+		if _, ok := duplicates[number]; ok {
+			continue
+		}
+		if _, ok := s.Entry(number); !ok {
+			items = append(items, Item{Change: Untracked, Number: number, Path: f.Path, Post: remoteByNumber[number]})
+		}
+	}
+	for number, post := range remoteByNumber {
+		_, tracked := s.Entry(number)
+		_, hasLocal := localByNumber[number]
+		if !tracked && !hasLocal {
+			items = append(items, Item{Change: NewRemote, Number: number, Post: post})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Number != items[j].Number {
+			return items[i].Number < items[j].Number
+		}
+		return items[i].Path < items[j].Path
+	})
+	return items
+}
+
+// RemoteMoved ローカルファイルを最後に取得（同期）してからリモートの記事が更新されたか
+// 同期状態が無いファイルは Front Matterの remote_updated_at で判定し、それも無ければ false
+func RemoteMoved(e *Entry, fm types.FrontMatter, post *types.Post) bool {
+	if e != nil {
+		return remoteMoved(e, post)
+	}
+	fetchedAt, err := time.Parse(time.RFC3339, fm.RemoteUpdatedAt)
+	if err != nil {
+		return false
+	}
+	return post.UpdatedAt.After(fetchedAt)
+}
+
+// remoteMoved 最後に同期してからリモートの記事が更新されたか
+// リビジョン番号が分からない場合は更新日時で判定する
+func remoteMoved(e *Entry, post *types.Post) bool {
+	if e.Revision > 0 && post.RevisionNumber > 0 {
+		return post.RevisionNumber != e.Revision
+	}
+	return !post.UpdatedAt.Equal(e.UpdatedAt)
+}
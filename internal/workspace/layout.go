@@ -0,0 +1,138 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/naming"
+)
+
+// CategoryPath カテゴリをディレクトリにした配置で、カテゴリが変わった記事ファイルの移動先を返す
+// ファイル名はそのままで、元のカテゴリのディレクトリを新しいカテゴリのディレクトリに置き換える
+// ファイルが元のカテゴリのディレクトリに無い（配置が異なる）場合やカテゴリが同じ場合は false を返す
+func CategoryPath(path, oldCategory, newCategory string) (string, bool) {
+	oldCategory = naming.CategoryDir(oldCategory)
+	newCategory = naming.CategoryDir(newCategory)
+	if oldCategory == newCategory {
+		return path, false
+	}
+	dir := filepath.Dir(path)
+	base := dir
+	if oldCategory != "" {
+		suffix := filepath.FromSlash(oldCategory)
+		if dir != suffix && !strings.HasSuffix(dir, string(filepath.Separator)+suffix) {
+			return path, false
+		}
+		base = strings.TrimSuffix(strings.TrimSuffix(dir, suffix), string(filepath.Separator))
+		if base == "" {
+			base = "."
+		}
+	}
+	return filepath.Join(base, filepath.FromSlash(newCategory), filepath.Base(path)), true
+}
+
+// MoveFile ファイルを移動する
+// 移動先のディレクトリは作成し、移動して空になったディレクトリは root（ワークスペースのルート）の手前まで削除する
+func MoveFile(from, to, root string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("移動先にファイルがあります: %s", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	// 空でなくなったところで止まる（削除に失敗する）
+	for dir := filepath.Dir(from); within(root, dir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// within dir が root の配下にあるか（root 自身は含まない）
+func within(root, dir string) bool {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// RelPath カレントディレクトリからの相対パス（表示用）
+func RelPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// Move 記事ファイルを移動したことを記録する
+func (s *State) Move(number int, path string) {
+	if e, ok := s.Entry(number); ok {
+		e.Path = s.Rel(path)
+	}
+}
+
+// Relocate 記事ファイルが索引の別の場所にあれば path に移動する
+// tree の配置で、カテゴリが変わった記事や tree にする前に保存したファイルを保存先に揃えるのに使う
+// 移動した場合は移動元のパスを返す（移動しなければ空文字）
+func (ix *Index) Relocate(number int, path string) (string, error) {
+	from, ok := ix.Path(number)
+	if !ok {
+		return "", nil
+	}
+	to, err := filepath.Abs(path)
+	if err != nil || from == to {
+		return "", err
+	}
+	if err := MoveFile(from, to, ix.root); err != nil {
+		return "", err
+	}
+	delete(ix.numbers, from)
+	ix.Add(number, to)
+	return from, nil
+}
+
+// PostPath 記事番号 number の記事を保存するパス
+// path に別の記事や記事番号の無いファイルがあれば、上書きしないよう番号を付けたパス（123-設計-2.md）を返す
+func PostPath(path string, number int) string {
+	return naming.Unique(path, func(p string) bool {
+		if _, err := os.Stat(p); err != nil {
+			return false
+		}
+		n, ok := readNumber(p)
+		return !ok || n != number
+	})
+}
+
+// Trash リモートで削除された記事のファイルを .trash（ワークスペースのルート直下）に移動し、同期状態から外す
+// .trash にはルートからの相対パスのまま置き、同じパスのファイルがあれば番号を付ける
+// 移動先のパスを返す
+func (s *State) Trash(number int, path string) (string, error) {
+	to := naming.Unique(filepath.Join(s.root, TrashDir, filepath.FromSlash(s.Rel(path))), func(p string) bool {
+		_, err := os.Stat(p)
+		return err == nil
+	})
+	if err := MoveFile(path, to, s.root); err != nil {
+		return "", err
+	}
+	s.Untrack(number)
+	return to, nil
+}
@@ -0,0 +1,186 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestCategoryPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		oldCategory string
+		newCategory string
+		want        string
+		wantOK      bool
+	}{
+		{
+			name:        "正常系：カテゴリのディレクトリを置き換える",
+			path:        "docs/開発/インフラ/123-設計.md",
+			oldCategory: "開発/インフラ",
+			newCategory: "設計/インフラ",
+			want:        "docs/設計/インフラ/123-設計.md",
+			wantOK:      true,
+		},
+		{
+			name:        "正常系：ルート直下のカテゴリ",
+			path:        "開発/123-設計.md",
+			oldCategory: "開発",
+			newCategory: "アーカイブ/開発",
+			want:        "アーカイブ/開発/123-設計.md",
+			wantOK:      true,
+		},
+		{
+			name:        "正常系：カテゴリの無い記事にカテゴリを付ける",
+			path:        "123-設計.md",
+			newCategory: "開発",
+			want:        "開発/123-設計.md",
+			wantOK:      true,
+		},
+		{
+			name:        "正常系：カテゴリを外すとルートに置く",
+			path:        "開発/123-設計.md",
+			oldCategory: "開発",
+			want:        "123-設計.md",
+			wantOK:      true,
+		},
+		{
+			name:        "異常系：カテゴリのディレクトリに無いファイルは移動しない",
+			path:        "メモ/123-設計.md",
+			oldCategory: "開発",
+			newCategory: "設計",
+			want:        "メモ/123-設計.md",
+		},
+		{
+			name:        "エッジケース：ディレクトリ名の一部だけが一致する場合は移動しない",
+			path:        "新開発/123-設計.md",
+			oldCategory: "開発",
+			newCategory: "設計",
+			want:        "新開発/123-設計.md",
+		},
+		{
+			name:        "エッジケース：カテゴリが同じなら移動しない",
+			path:        "開発/123-設計.md",
+			oldCategory: "開発/",
+			newCategory: "開発",
+			want:        "開発/123-設計.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CategoryPath(filepath.FromSlash(tt.path), tt.oldCategory, tt.newCategory)
+			if filepath.ToSlash(got) != tt.want || ok != tt.wantOK {
+				t.Errorf("CategoryPath() = %v, %v, want %v, %v", filepath.ToSlash(got), ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMoveFile(t *testing.T) {
+	root := t.TempDir()
+	from := filepath.Join(root, "開発", "インフラ", "123-設計.md")
+	to := filepath.Join(root, "設計", "123-設計.md")
+	writeFile(t, from, "本文")
+	writeFile(t, filepath.Join(root, "開発", "124-手順.md"), "本文")
+
+	if err := MoveFile(from, to, root); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if _, err := os.Stat(to); err != nil {
+		t.Errorf("移動先のファイルがありません: %v", err)
+	}
+	// 空になったディレクトリだけを削除する
+	if _, err := os.Stat(filepath.Join(root, "開発", "インフラ")); !os.IsNotExist(err) {
+		t.Errorf("空になったディレクトリが残っています")
+	}
+	if _, err := os.Stat(filepath.Join(root, "開発")); err != nil {
+		t.Errorf("ファイルのあるディレクトリが削除されました: %v", err)
+	}
+
+	// 移動先にファイルがあれば上書きしない
+	writeFile(t, from, "別の本文")
+	if err := MoveFile(from, to, root); err == nil {
+		t.Error("MoveFile() error = nil, want error")
+	}
+}
+
+func TestMoveFile_StopsAtRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "ワークスペース")
+	from := filepath.Join(root, "開発", "123-設計.md")
+	to := filepath.Join(parent, "移動先", "123-設計.md")
+	writeFile(t, from, "本文")
+
+	if err := MoveFile(from, to, root); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "開発")); !os.IsNotExist(err) {
+		t.Errorf("空になったディレクトリが残っています")
+	}
+	// 空になってもワークスペースのルートとその外側は削除しない
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("ワークスペースのルートが削除されました: %v", err)
+	}
+}
+
+func TestPostPath(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "設計.md"), "---\ntitle: 設計\nnumber: 1\n---\n本文\n")
+	writeFile(t, filepath.Join(root, "メモ.md"), "記事番号の無いファイル\n")
+
+	tests := []struct {
+		name   string
+		file   string
+		number int
+		want   string
+	}{
+		{name: "正常系：ファイルが無ければそのまま", file: "手順.md", number: 2, want: "手順.md"},
+		{name: "正常系：同じ記事のファイルは上書きする", file: "設計.md", number: 1, want: "設計.md"},
+		{name: "正常系：別の記事のファイルがあれば番号を付ける", file: "設計.md", number: 2, want: "設計-2.md"},
+		{name: "正常系：記事番号の無いファイルは上書きしない", file: "メモ.md", number: 3, want: "メモ-2.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PostPath(filepath.Join(root, tt.file), tt.number)
+			if want := filepath.Join(root, tt.want); got != want {
+				t.Errorf("PostPath() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestState_Trash(t *testing.T) {
+	root := t.TempDir()
+	state, err := LoadState(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "---\ntitle: 設計\nnumber: 1\n---\n本文\n"
+	path := filepath.Join(root, "開発", "1-設計.md")
+	writeFile(t, path, content)
+	state.Track(&types.Post{Number: 1, RevisionNumber: 1}, path, []byte(content))
+	// 以前に削除した同じパスのファイルは上書きしない
+	writeFile(t, filepath.Join(root, TrashDir, "開発", "1-設計.md"), "以前の本文")
+
+	got, err := state.Trash(1, path)
+	if err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+	if want := filepath.Join(root, TrashDir, "開発", "1-設計-2.md"); got != want {
+		t.Errorf("Trash() = %v, want %v", got, want)
+	}
+	if b, err := os.ReadFile(got); err != nil || string(b) != content {
+		t.Errorf("移動先の内容 = %q, %v", b, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("移動元のファイルが残っています")
+	}
+	if _, ok := state.Entry(1); ok {
+		t.Errorf("同期状態に記事が残っています")
+	}
+}
@@ -0,0 +1,53 @@
+package workspace
+
+import "github.com/shellme/esa-cli/pkg/types"
+
+// Meta 記事のメタデータ（フロントマターで編集できる項目）
+type Meta struct {
+	Title    string   `json:"title"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags,omitempty"`
+	Wip      bool     `json:"wip"`
+}
+
+// PostMeta リモートの記事のメタデータ
+func PostMeta(post *types.Post) *Meta {
+	return &Meta{Title: post.Name, Category: post.Category, Tags: post.Tags, Wip: post.Wip}
+}
+
+// FrontMatterMeta ローカルファイルのフロントマターのメタデータ
+func FrontMatterMeta(fm types.FrontMatter) *Meta {
+	return &Meta{Title: fm.Title, Category: fm.Category, Tags: fm.Tags, Wip: fm.Wip}
+}
+
+// Apply メタデータをフロントマターに書き込む
+func (m *Meta) Apply(fm *types.FrontMatter) {
+	fm.Title = m.Title
+	fm.Category = m.Category
+	fm.Tags = m.Tags
+	fm.Wip = m.Wip
+}
+
+// MergeMeta ローカルとリモートのメタデータを項目ごとにベースと3-wayマージする
+// 片方だけが変更した項目はその値を使い、両方が別の値に変更した項目はローカルの値を残す
+// ローカルの値を残したためにリモートの変更を反映できなかった項目の名前を返す
+// base が nil（以前の同期状態）なら、ローカルとリモートで異なる項目はすべてローカルの値を残す
+func MergeMeta(base, local, remote *Meta) (*Meta, []string) {
+	merged := *local
+	var dropped []string
+	pick := func(name string, same func(a, b *Meta) bool, take func()) {
+		switch {
+		case same(local, remote):
+		case base != nil && same(base, remote):
+		case base != nil && same(base, local):
+			take()
+		default:
+			dropped = append(dropped, name)
+		}
+	}
+	pick("タイトル", func(a, b *Meta) bool { return a.Title == b.Title }, func() { merged.Title = remote.Title })
+	pick("カテゴリ", func(a, b *Meta) bool { return a.Category == b.Category }, func() { merged.Category = remote.Category })
+	pick("タグ", func(a, b *Meta) bool { return sameTags(a.Tags, b.Tags) }, func() { merged.Tags = remote.Tags })
+	pick("WIP", func(a, b *Meta) bool { return a.Wip == b.Wip }, func() { merged.Wip = remote.Wip })
+	return &merged, dropped
+}
@@ -0,0 +1,60 @@
+package workspace
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeMeta(t *testing.T) {
+	base := &Meta{Title: "記事", Category: "開発", Tags: []string{"a"}, Wip: true}
+	tests := []struct {
+		name        string
+		base        *Meta
+		local       *Meta
+		remote      *Meta
+		want        *Meta
+		wantDropped []string
+	}{
+		{
+			name:   "正常系：片方だけが変更した項目はその値を使う",
+			base:   base,
+			local:  &Meta{Title: "新しい記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			remote: &Meta{Title: "記事", Category: "開発/設計", Tags: []string{"a", "b"}, Wip: false},
+			want:   &Meta{Title: "新しい記事", Category: "開発/設計", Tags: []string{"a", "b"}, Wip: false},
+		},
+		{
+			name:        "正常系：両方が別の値に変更した項目はローカルの値を残す",
+			base:        base,
+			local:       &Meta{Title: "ローカルの記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			remote:      &Meta{Title: "リモートの記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			want:        &Meta{Title: "ローカルの記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			wantDropped: []string{"タイトル"},
+		},
+		{
+			name:   "正常系：タグの順序だけの違いは変更として扱わない",
+			base:   base,
+			local:  &Meta{Title: "記事", Category: "開発", Tags: []string{"b", "a"}, Wip: true},
+			remote: &Meta{Title: "記事", Category: "開発", Tags: []string{"a", "b"}, Wip: true},
+			want:   &Meta{Title: "記事", Category: "開発", Tags: []string{"b", "a"}, Wip: true},
+		},
+		{
+			name:        "エッジケース：ベースが無ければ異なる項目はすべてローカルの値を残す",
+			local:       &Meta{Title: "記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			remote:      &Meta{Title: "記事", Category: "設計", Tags: []string{"a"}, Wip: false},
+			want:        &Meta{Title: "記事", Category: "開発", Tags: []string{"a"}, Wip: true},
+			wantDropped: []string{"カテゴリ", "WIP"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped := MergeMeta(tt.base, tt.local, tt.remote)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeMeta() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("MergeMeta() dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+const (
+	// StateDir ワークスペースの状態を保存するディレクトリ（ワークスペースのルート直下）
+	StateDir = ".esa-cli"
+	// StateFile 同期状態を記録するファイル名
+	StateFile = "state.json"
+	// BaseDir 最後に取得したリモートの本文（3-wayマージのベース）を保存するディレクトリ
+	BaseDir = "base"
+	// TrashDir リモートで削除された記事のファイルを移動するディレクトリ（ワークスペースのルート直下）
+	TrashDir = ".trash"
+)
+
+// Entry 記事ごとの最後に同期したときの状態
+type Entry struct {
+	Number int `json:"number"`
+	// Path ワークスペースのルートからの相対パス（区切り文字は /）
+	Path string `json:"path"`
+	// Revision 最後に同期したときのリモートのリビジョン番号
+	Revision int `json:"revision_number"`
+	// Hash 最後に同期したときのローカルファイルの内容のハッシュ
+	Hash string `json:"hash"`
+	// UpdatedAt 最後に同期したときのリモートの更新日時
+	UpdatedAt time.Time `json:"updated_at"`
+	// Category 最後に同期したときの記事のカテゴリ（以前の同期状態では空）
+	Category string `json:"category,omitempty"`
+	// Meta 最後に同期したときの記事のメタデータ（フロントマターの3-wayマージのベース。以前の同期状態では nil）
+	Meta *Meta `json:"meta,omitempty"`
+	// Conflict マージで競合マーカーを書き込んだ（Hash は書き込んだ内容のハッシュ）
+	// ファイルを編集するまでは競合として扱う
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// State ワークスペースの同期状態（.esa-cli/state.json）
+type State struct {
+	// Posts 記事番号 → 同期状態
+	Posts map[string]*Entry `json:"posts"`
+	// LastPull カテゴリ → 最後に pull した時刻（次回の pull はこれ以降に更新された記事だけを取得する）
+	LastPull map[string]time.Time `json:"last_pull,omitempty"`
+
+	root string
+	// bases 保存するベース（記事番号 → 本文）
+	bases map[int]string
+}
+
+// Hash ファイルの内容のハッシュ
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadState ワークスペースの同期状態を読み込む（ファイルが無ければ空の状態を返す）
+func LoadState(root string) (*State, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	s := &State{Posts: map[string]*Entry{}, root: root, bases: map[int]string{}}
+
+	data, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", s.path(), err)
+	}
+	if s.Posts == nil {
+		s.Posts = map[string]*Entry{}
+	}
+	return s, nil
+}
+
+// LoadWorkspaceState 現在のワークスペースの同期状態を読み込む
+func LoadWorkspaceState() (*State, error) {
+	root, err := Root()
+	if err != nil {
+		return nil, err
+	}
+	return LoadState(root)
+}
+
+func (s *State) path() string {
+	return filepath.Join(s.root, StateDir, StateFile)
+}
+
+// Root ワークスペースのルートディレクトリ
+func (s *State) Root() string {
+	return s.root
+}
+
+// Save 同期状態とベースを保存する
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path()), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path(), append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return s.SaveBases()
+}
+
+func (s *State) basePath(number int) string {
+	return filepath.Join(s.root, StateDir, BaseDir, strconv.Itoa(number)+".md")
+}
+
+// Base 最後に取得したリモートの本文を返す（無ければ false）
+func (s *State) Base(number int) (string, bool) {
+	if body, ok := s.bases[number]; ok {
+		return body, true
+	}
+	data, err := os.ReadFile(s.basePath(number))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// SetBase 最後に取得したリモートの本文を記録する（SaveBases で保存する）
+func (s *State) SetBase(number int, body string) {
+	s.bases[number] = body
+}
+
+// SaveBases 記録したベースだけを保存する
+// sync を実行していないワークスペースでも update の3-wayマージに使えるよう、同期状態とは別に保存する
+func (s *State) SaveBases() error {
+	for number, body := range s.bases {
+		path := s.basePath(number)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InCategory カテゴリ配下の記事として同期したか（カテゴリが空、または記録が無ければ true）
+func (e *Entry) InCategory(category string) bool {
+	category = strings.Trim(category, "/")
+	if category == "" || e.Category == "" {
+		return true
+	}
+	return e.Category == category || strings.HasPrefix(e.Category, category+"/")
+}
+
+// Entry 記事の同期状態を返す
+func (s *State) Entry(number int) (*Entry, bool) {
+	e, ok := s.Posts[strconv.Itoa(number)]
+	return e, ok
+}
+
+// Entries 同期状態を記事番号順に返す
+func (s *State) Entries() []*Entry {
+	entries := make([]*Entry, 0, len(s.Posts))
+	for _, e := range s.Posts {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Number < entries[j].Number })
+	return entries
+}
+
+// Track 記事とローカルファイルを同期済みとして記録する
+// content は書き込んだ（または読み込んだ）ローカルファイルの内容
+func (s *State) Track(post *types.Post, path string, content []byte) {
+	s.Posts[strconv.Itoa(post.Number)] = &Entry{
+		Number:    post.Number,
+		Path:      s.Rel(path),
+		Revision:  post.RevisionNumber,
+		Hash:      Hash(content),
+		UpdatedAt: post.UpdatedAt,
+		Category:  post.Category,
+		Meta:      PostMeta(post),
+	}
+	s.SetBase(post.Number, post.BodyMd)
+}
+
+// Rebase ローカルファイルの変更を残したまま、記録するリモートの版とベースだけを進める
+// マージした結果をまだアップロードしていない場合に使う（ローカルの変更として扱われる）
+func (s *State) Rebase(post *types.Post, path string) {
+	hash := ""
+	if e, ok := s.Entry(post.Number); ok {
+		hash = e.Hash
+	}
+	s.Posts[strconv.Itoa(post.Number)] = &Entry{
+		Number:    post.Number,
+		Path:      s.Rel(path),
+		Revision:  post.RevisionNumber,
+		Hash:      hash,
+		UpdatedAt: post.UpdatedAt,
+		Category:  post.Category,
+		Meta:      PostMeta(post),
+	}
+	s.SetBase(post.Number, post.BodyMd)
+}
+
+// RecordConflict マージで競合したことを記録する
+// リモートの版とベースは進め、競合マーカーを書き込んだ content のハッシュを記録する
+// ファイルを編集する（競合を解消する）までは Compare が Conflict を返し、競合マーカーのままアップロードされないようにする
+func (s *State) RecordConflict(post *types.Post, path string, content []byte) {
+	s.Track(post, path, content)
+	s.Posts[strconv.Itoa(post.Number)].Conflict = true
+}
+
+// LastPulled カテゴリを最後に pull した時刻（pull していなければゼロ値）
+func (s *State) LastPulled(category string) time.Time {
+	return s.LastPull[category]
+}
+
+// SetLastPull カテゴリを pull した時刻を記録する
+func (s *State) SetLastPull(category string, t time.Time) {
+	if s.LastPull == nil {
+		s.LastPull = map[string]time.Time{}
+	}
+	s.LastPull[category] = t
+}
+
+// Untrack 記事の同期状態を削除する
+func (s *State) Untrack(number int) {
+	delete(s.Posts, strconv.Itoa(number))
+}
+
+// Abs 記録されたパスの絶対パス
+func (s *State) Abs(e *Entry) string {
+	return filepath.Join(s.root, filepath.FromSlash(e.Path))
+}
+
+// Rel ワークスペースのルートからの相対パス（区切り文字は /）
+func (s *State) Rel(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Exists 同期状態のファイルがあるか（sync を実行したワークスペースか）
+func (s *State) Exists() bool {
+	_, err := os.Stat(s.path())
+	return err == nil
+}
+
+// Track 記事を取得・更新したファイルを現在のワークスペースの同期状態に記録する
+// sync を実行していないワークスペースではベースだけを保存し、ワークスペースの外では何も保存しない
+func Track(post *types.Post, path string, content []byte) error {
+	s, err := LoadWorkspaceState()
+	if err != nil {
+		return err
+	}
+	s.Track(post, path, content)
+	return s.SaveTracked()
+}
+
+// SaveTracked 記事を取得・更新したコマンドの記録を保存する
+// sync・pull を実行したワークスペースでは同期状態ごと保存し、実行していないワークスペースではベースだけを保存する
+// ワークスペースの外では何も保存しない（.esa-cli/ を作るとそこがワークスペースとして扱われてしまうため）
+func (s *State) SaveTracked() error {
+	switch {
+	case s.Exists():
+		return s.Save()
+	case isWorkspace(s.root):
+		return s.SaveBases()
+	}
+	return nil
+}
+
+// isWorkspace ワークスペースとして使っているディレクトリか（.esa-cli.yml か .esa-cli/ がある）
+func isWorkspace(root string) bool {
+	if _, err := os.Stat(filepath.Join(root, config.ProjectFileName)); err == nil {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(root, StateDir))
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,316 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestState_SaveAndLoad(t *testing.T) {
+	root := t.TempDir()
+	state, err := LoadState(root)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.Exists() {
+		t.Error("Exists() = true before Save")
+	}
+
+	updatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	post := &types.Post{Number: 12, RevisionNumber: 3, UpdatedAt: updatedAt}
+	state.Track(post, filepath.Join(root, "docs", "12-設計.md"), []byte("content"))
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(root)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if !loaded.Exists() {
+		t.Error("Exists() = false after Save")
+	}
+	e, ok := loaded.Entry(12)
+	if !ok {
+		t.Fatal("Entry(12) not found")
+	}
+	if e.Path != "docs/12-設計.md" || e.Revision != 3 || e.Hash != Hash([]byte("content")) || !e.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Entry(12) = %+v", e)
+	}
+	if got := loaded.Abs(e); got != filepath.Join(root, "docs", "12-設計.md") {
+		t.Errorf("Abs() = %q", got)
+	}
+
+	loaded.Untrack(12)
+	if _, ok := loaded.Entry(12); ok {
+		t.Error("Entry(12) found after Untrack")
+	}
+}
+
+func TestState_Base(t *testing.T) {
+	root := t.TempDir()
+	state, _ := LoadState(root)
+	if _, ok := state.Base(1); ok {
+		t.Error("Base() = true before Track")
+	}
+
+	state.Track(&types.Post{Number: 1, BodyMd: "リモートの本文"}, filepath.Join(root, "1-記事.md"), []byte("内容"))
+	if err := state.SaveBases(); err != nil {
+		t.Fatalf("SaveBases() error = %v", err)
+	}
+	if state.Exists() {
+		t.Error("SaveBases() で同期状態が作成されました")
+	}
+
+	loaded, _ := LoadState(root)
+	if got, ok := loaded.Base(1); !ok || got != "リモートの本文" {
+		t.Errorf("Base() = %q, %v, want %q", got, ok, "リモートの本文")
+	}
+}
+
+func TestState_Compare(t *testing.T) {
+	root := t.TempDir()
+	state, _ := LoadState(root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := func(name string) string { return filepath.Join(root, name) }
+
+	// 1: 変更なし 2: ローカルのみ変更 3: リモートのみ変更 4: 両方変更
+	// 5: リモートで削除 6: ローカルで削除
+	for n := 1; n <= 6; n++ {
+		state.Track(&types.Post{Number: n, RevisionNumber: 1, UpdatedAt: t0}, path(fmt.Sprintf("%d.md", n)), []byte("synced"))
+	}
+	local := []LocalFile{
+		{Path: path("1.md"), Number: 1, Hash: Hash([]byte("synced"))},
+		{Path: path("2.md"), Number: 2, Hash: Hash([]byte("edited"))},
+		{Path: path("3.md"), Number: 3, Hash: Hash([]byte("synced"))},
+		{Path: path("4.md"), Number: 4, Hash: Hash([]byte("edited"))},
+		{Path: path("5.md"), Number: 5, Hash: Hash([]byte("synced"))},
+		{Path: path("7.md"), Number: 7, Hash: Hash([]byte("fetched"))},
+		{Path: path("draft.md"), Hash: Hash([]byte("draft"))},
+	}
+	remote := []*types.Post{
+		{Number: 1, RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 2, RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 3, RevisionNumber: 2, UpdatedAt: t0.Add(time.Hour)},
+		{Number: 4, RevisionNumber: 2, UpdatedAt: t0.Add(time.Hour)},
+		{Number: 6, RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 7, RevisionNumber: 1, UpdatedAt: t0},
+		{Number: 8, RevisionNumber: 1, UpdatedAt: t0},
+	}
+
+	got := map[int]Change{}
+	var newLocal []string
+	for _, item := range state.Compare(local, remote) {
+		if item.Change == NewLocal {
+			newLocal = append(newLocal, item.Path)
+			continue
+		}
+		got[item.Number] = item.Change
+	}
+
+	want := map[int]Change{
+		1: Unchanged,
+		2: LocalModified,
+		3: RemoteModified,
+		4: Conflict,
+		5: RemoteDeleted,
+		6: LocalDeleted,
+		7: Untracked,
+		8: NewRemote,
+	}
+	for number, change := range want {
+		if got[number] != change {
+			t.Errorf("Compare()[%d] = %q, want %q", number, got[number], change)
+		}
+	}
+	if len(newLocal) != 1 || newLocal[0] != path("draft.md") {
+		t.Errorf("Compare() new local = %v", newLocal)
+	}
+}
+
+func TestState_CompareWithoutRevision(t *testing.T) {
+	root := t.TempDir()
+	state, _ := LoadState(root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	state.Track(&types.Post{Number: 1, UpdatedAt: t0}, filepath.Join(root, "1.md"), []byte("synced"))
+
+	local := []LocalFile{{Path: filepath.Join(root, "1.md"), Number: 1, Hash: Hash([]byte("synced"))}}
+	remote := []*types.Post{{Number: 1, UpdatedAt: t0.Add(time.Minute)}}
+	items := state.Compare(local, remote)
+	if len(items) != 1 || items[0].Change != RemoteModified {
+		t.Errorf("Compare() = %+v, want remote_modified by updated_at", items)
+	}
+}
+
+func TestScanFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "12-設計.md"), "---\ntitle: 設計\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, "docs", "下書き.md"), "---\ntitle: 下書き\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, "ignored", "34-除外.md"), "本文\n")
+	writeFile(t, filepath.Join(root, StateDir, "56-隠し.md"), "本文\n")
+	writeFile(t, filepath.Join(root, "image.png"), "png")
+
+	files, err := ScanFiles(root, func(path string) bool {
+		return filepath.Base(filepath.Dir(path)) == "ignored"
+	})
+	if err != nil {
+		t.Fatalf("ScanFiles() error = %v", err)
+	}
+	got := map[string]int{}
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f.Path)
+		got[filepath.ToSlash(rel)] = f.Number
+	}
+	want := map[string]int{"12-設計.md": 12, "docs/下書き.md": 0}
+	if len(got) != len(want) {
+		t.Fatalf("ScanFiles() = %v, want %v", got, want)
+	}
+	for path, number := range want {
+		if n, ok := got[path]; !ok || n != number {
+			t.Errorf("ScanFiles()[%s] = %d, %v, want %d", path, n, ok, number)
+		}
+	}
+}
+
+func TestState_CompareIn(t *testing.T) {
+	root := t.TempDir()
+	state, _ := LoadState(root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := func(name string) string { return filepath.Join(root, name) }
+
+	// 1: カテゴリ内 2: 別カテゴリ 3: 別カテゴリからカテゴリ内に移動 4: カテゴリの記録が無い
+	categories := map[int]string{1: "開発/メモ", 2: "設計", 3: "設計", 4: ""}
+	var local []LocalFile
+	for n := 1; n <= 4; n++ {
+		state.Track(&types.Post{Number: n, Category: categories[n], RevisionNumber: 1, UpdatedAt: t0}, path(fmt.Sprintf("%d.md", n)), []byte("synced"))
+		local = append(local, LocalFile{Path: path(fmt.Sprintf("%d.md", n)), Number: n, Hash: Hash([]byte("synced"))})
+	}
+	remote := []*types.Post{{Number: 3, Category: "開発", RevisionNumber: 2, UpdatedAt: t0.Add(time.Hour)}}
+
+	got := map[int]Change{}
+	for _, item := range state.CompareIn("開発", local, remote) {
+		got[item.Number] = item.Change
+	}
+	want := map[int]Change{1: RemoteDeleted, 3: RemoteModified, 4: RemoteDeleted}
+	if len(got) != len(want) {
+		t.Errorf("CompareIn() = %v, want %v", got, want)
+	}
+	for number, change := range want {
+		if got[number] != change {
+			t.Errorf("CompareIn()[%d] = %q, want %q", number, got[number], change)
+		}
+	}
+}
+
+func TestState_CompareDuplicateNumber(t *testing.T) {
+	root := t.TempDir()
+	state, _ := LoadState(root)
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	state.Track(&types.Post{Number: 1, RevisionNumber: 1, UpdatedAt: t0}, filepath.Join(root, "1.md"), []byte("synced"))
+
+	local := []LocalFile{
+		{Path: filepath.Join(root, "1.md"), Number: 1, Hash: Hash([]byte("synced"))},
+		{Path: filepath.Join(root, "copy", "1.md"), Number: 1, Hash: Hash([]byte("edited"))},
+	}
+	remote := []*types.Post{{Number: 1, RevisionNumber: 1, UpdatedAt: t0}}
+	items := state.Compare(local, remote)
+	if len(items) != 2 {
+		t.Fatalf("Compare() = %+v, want 2 items", items)
+	}
+	for _, item := range items {
+		if item.Change != Conflict || !item.Duplicate {
+			t.Errorf("Compare() %s = %q (duplicate %v), want duplicate conflict", item.Path, item.Change, item.Duplicate)
+		}
+	}
+}
+
+func TestTrack(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, root string)
+		wantBase bool
+	}{
+		{name: "正常系：.esa-cli.yml があればベースを保存する", setup: func(t *testing.T, root string) {
+			writeFile(t, filepath.Join(root, config.ProjectFileName), "category: 開発\n")
+		}, wantBase: true},
+		{name: "正常系：.esa-cli/ があればベースを保存する", setup: func(t *testing.T, root string) {
+			if err := os.Mkdir(filepath.Join(root, StateDir), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}, wantBase: true},
+		{name: "エッジケース：ワークスペースの外では何も保存しない", setup: func(t *testing.T, root string) {}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			tt.setup(t, root)
+			wd, _ := os.Getwd()
+			defer os.Chdir(wd)
+			if err := os.Chdir(root); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(root, "1-手順.md")
+			if err := Track(&types.Post{Number: 1, BodyMd: "本文"}, path, []byte("本文")); err != nil {
+				t.Fatalf("Track() error = %v", err)
+			}
+			_, err := os.Stat(filepath.Join(root, StateDir, BaseDir, "1.md"))
+			if (err == nil) != tt.wantBase {
+				t.Errorf("ベースの保存 = %v, want %v", err == nil, tt.wantBase)
+			}
+			if _, err := os.Stat(filepath.Join(root, StateDir)); !tt.wantBase && err == nil {
+				t.Errorf("%s が作成されました", StateDir)
+			}
+		})
+	}
+}
+
+func TestState_SaveTracked(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, root string)
+		wantBase  bool
+		wantState bool
+	}{
+		{name: "正常系：同期状態があれば同期状態ごと保存する", setup: func(t *testing.T, root string) {
+			writeFile(t, filepath.Join(root, StateDir, StateFile), "{\"posts\": {}}\n")
+		}, wantBase: true, wantState: true},
+		{name: "正常系：.esa-cli.yml だけならベースだけを保存する", setup: func(t *testing.T, root string) {
+			writeFile(t, filepath.Join(root, config.ProjectFileName), "category: 開発\n")
+		}, wantBase: true},
+		{name: "エッジケース：ワークスペースの外では .esa-cli/ を作らない", setup: func(t *testing.T, root string) {}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			tt.setup(t, root)
+			state, err := LoadState(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state.Track(&types.Post{Number: 1, BodyMd: "本文"}, filepath.Join(root, "1-手順.md"), []byte("本文"))
+			if err := state.SaveTracked(); err != nil {
+				t.Fatalf("SaveTracked() error = %v", err)
+			}
+
+			if _, err := os.Stat(state.basePath(1)); (err == nil) != tt.wantBase {
+				t.Errorf("ベースの保存 = %v, want %v", err == nil, tt.wantBase)
+			}
+			if _, err := os.Stat(filepath.Join(root, StateDir)); !tt.wantBase && err == nil {
+				t.Errorf("%s が作成されました", StateDir)
+			}
+			data, _ := os.ReadFile(filepath.Join(root, StateDir, StateFile))
+			if saved := strings.Contains(string(data), `"1"`); saved != tt.wantState {
+				t.Errorf("同期状態の保存 = %v, want %v", saved, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/diff"
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+// Summary アップロードする内容とリモートの記事との違いの要約（例: "title, tags, 本文 +3 -1"）
+// body はアップロードする形（画像・リンクを元に戻した状態）の本文。違いが無ければ空文字を返す
+func Summary(post *types.Post, fm types.FrontMatter, body string) string {
+	var parts []string
+	if fm.Title != post.Name {
+		parts = append(parts, "title")
+	}
+	if fm.Category != post.Category {
+		parts = append(parts, "category")
+	}
+	if !sameTags(fm.Tags, post.Tags) {
+		parts = append(parts, "tags")
+	}
+	if fm.Wip != post.Wip {
+		parts = append(parts, "wip")
+	}
+	if added, deleted := diff.Stat(diff.Lines(post.BodyMd, body)); added+deleted > 0 {
+		parts = append(parts, fmt.Sprintf("本文 +%d -%d", added, deleted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sameTags タグが同じか（順序は問わない）
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shellme/esa-cli/pkg/types"
+)
+
+func TestSummary(t *testing.T) {
+	post := &types.Post{Name: "記事", Category: "開発", Tags: []string{"a", "b"}, Wip: true, BodyMd: "一\r\n二\r\n三\r\n"}
+	tests := []struct {
+		name string
+		fm   types.FrontMatter
+		body string
+		want string
+	}{
+		{
+			name: "正常系：違いが無ければ空文字",
+			fm:   types.FrontMatter{Title: "記事", Category: "開発", Tags: []string{"b", "a"}, Wip: true},
+			body: "一\n二\n三\n",
+			want: "",
+		},
+		{
+			name: "正常系：メタデータと本文の違い",
+			fm:   types.FrontMatter{Title: "新しい記事", Category: "開発", Tags: []string{"a"}, Wip: false},
+			body: "一\n弐\n三\n四\n",
+			want: "title, tags, wip, 本文 +2 -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summary(post, tt.fm, tt.body); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteMoved(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	tests := []struct {
+		name  string
+		entry *Entry
+		fm    types.FrontMatter
+		post  *types.Post
+		want  bool
+	}{
+		{
+			name:  "正常系：同期状態のリビジョンで判定する",
+			entry: &Entry{Revision: 1, UpdatedAt: t0},
+			fm:    types.FrontMatter{RemoteUpdatedAt: t0.Format(time.RFC3339)},
+			post:  &types.Post{RevisionNumber: 2, UpdatedAt: t1},
+			want:  true,
+		},
+		{
+			name: "正常系：同期状態が無ければ remote_updated_at で判定する",
+			fm:   types.FrontMatter{RemoteUpdatedAt: t0.Format(time.RFC3339)},
+			post: &types.Post{RevisionNumber: 2, UpdatedAt: t1},
+			want: true,
+		},
+		{
+			name: "エッジケース：どちらも無ければ更新されていないものとする",
+			post: &types.Post{RevisionNumber: 2, UpdatedAt: t1},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemoteMoved(tt.entry, tt.fm, tt.post); got != tt.want {
+				t.Errorf("RemoteMoved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watcher ディレクトリ配下のMarkdownファイルの変更をポーリングで検出する
+// 保存が続いている間は待ち、最後の変更から debounce の間変化が無くなったファイルを返す
+type Watcher struct {
+	root     string
+	ignored  func(path string) bool
+	debounce time.Duration
+
+	stamps  map[string]stamp
+	pending map[string]time.Time
+}
+
+// stamp 変更検知に使うファイルの更新日時とサイズ
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher 監視を開始する
+// 開始時点のファイルは変更済みとして扱わない
+func NewWatcher(root string, ignored func(path string) bool, debounce time.Duration) (*Watcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{root: root, ignored: ignored, debounce: debounce, pending: map[string]time.Time{}}
+	if w.stamps, err = w.scan(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Poll 変更を検出し、変更が落ち着いたファイルを返す
+func (w *Watcher) Poll(now time.Time) ([]string, error) {
+	current, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	for path, st := range current {
+		if old, ok := w.stamps[path]; !ok || old != st {
+			w.pending[path] = now
+		}
+	}
+	// 削除されたファイルは対象外
+	for path := range w.pending {
+		if _, ok := current[path]; !ok {
+			delete(w.pending, path)
+		}
+	}
+	w.stamps = current
+
+	var ready []string
+	for path, changed := range w.pending {
+		if now.Sub(changed) >= w.debounce {
+			ready = append(ready, path)
+			delete(w.pending, path)
+		}
+	}
+	sort.Strings(ready)
+	return ready, nil
+}
+
+// Touch ファイルの現在の状態を変更済みとして扱わないよう記録する
+// 更新後に Front Matter を書き戻したときなど、自分で書き込んだ変更を無視するために使う
+func (w *Watcher) Touch(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(abs); err == nil {
+		w.stamps[abs] = stamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	delete(w.pending, abs)
+}
+
+func (w *Watcher) scan() (map[string]stamp, error) {
+	stamps := map[string]stamp{}
+	err := filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// 保存中に消えたファイル（エディタの一時ファイルなど）は無視する
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			if path != w.root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") || (w.ignored != nil && w.ignored(path)) {
+			return nil
+		}
+		stamps[path] = stamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stamps, nil
+}
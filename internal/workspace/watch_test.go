@@ -0,0 +1,91 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	root := t.TempDir()
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	debounce := 2 * time.Second
+
+	// 更新日時を明示して書き込む（ファイルシステムの時刻の精度に左右されないように）
+	save := func(name, content string, mtime time.Time) string {
+		path := filepath.Join(root, name)
+		writeFile(t, path, content)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := save("1-設計.md", "一", t0)
+	save(filepath.Join(StateDir, "base", "1.md"), "一", t0)
+
+	w, err := NewWatcher(root, nil, debounce)
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+
+	steps := []struct {
+		name   string
+		change func()
+		now    time.Duration
+		want   []string
+	}{
+		{
+			name: "正常系：開始時点のファイルは対象外",
+			now:  time.Second,
+		},
+		{
+			name:   "正常系：保存直後はまだ返さない",
+			change: func() { save("1-設計.md", "一二", t0.Add(time.Second)) },
+			now:    time.Second,
+		},
+		{
+			name:   "正常系：保存が続いている間は待つ",
+			change: func() { save("1-設計.md", "一二三", t0.Add(2*time.Second)) },
+			now:    2 * time.Second,
+		},
+		{
+			name: "正常系：変更が落ち着いたら返す",
+			now:  4 * time.Second,
+			want: []string{a},
+		},
+		{
+			name: "正常系：一度返したファイルは再度変更されるまで返さない",
+			now:  10 * time.Second,
+		},
+		{
+			name:   "正常系：自分で書き込んだ変更は無視する",
+			change: func() { w.Touch(save("1-設計.md", "一二三四", t0.Add(11*time.Second))) },
+			now:    20 * time.Second,
+		},
+		{
+			name:   "正常系：新しく作成されたファイルも対象",
+			change: func() { save("2-下書き.md", "下書き", t0.Add(21*time.Second)) },
+			now:    21 * time.Second,
+		},
+		{
+			name:   "エッジケース：落ち着く前に削除されたファイルは返さない",
+			change: func() { os.Remove(filepath.Join(root, "2-下書き.md")) },
+			now:    30 * time.Second,
+		},
+	}
+
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		got, err := w.Poll(t0.Add(step.now))
+		if err != nil {
+			t.Fatalf("%s: Poll() error = %v", step.name, err)
+		}
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: Poll() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shellme/esa-cli/internal/config"
+	"github.com/shellme/esa-cli/internal/markdown"
+	"github.com/shellme/esa-cli/internal/naming"
+)
+
+// Root ワークスペースのルートディレクトリ
+// プロジェクト設定(.esa-cli.yml)があればその場所、無ければカレントディレクトリ
+func Root() (string, error) {
+	if project, err := config.LoadProjectConfig(); err == nil && project != nil {
+		return project.Root(), nil
+	}
+	return os.Getwd()
+}
+
+// Index ワークスペース内の記事ファイルの索引
+type Index struct {
+	root    string
+	paths   map[int]string
+	numbers map[string]int
+}
+
+// Scan rootディレクトリ配下のMarkdownファイルから索引を作成する
+// 記事番号はFront Matterの number を優先し、無ければファイル名の先頭から取得する
+func Scan(root string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Index{root: root, paths: map[int]string{}, numbers: map[string]int{}}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// .git や .esa-cli などの隠しディレクトリはスキップ
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		if number, ok := readNumber(path); ok {
+			ix.Add(number, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// readNumber ファイルの記事番号を取得する
+func readNumber(path string) (int, bool) {
+	if content, err := os.ReadFile(path); err == nil {
+		var fm struct {
+			Number int `yaml:"number"`
+		}
+		if doc, err := markdown.Parse(content); err == nil && doc.Decode(&fm) == nil && fm.Number > 0 {
+			return fm.Number, true
+		}
+	}
+	return naming.NumberFromFileName(path)
+}
+
+// Root 索引のルートディレクトリ
+func (ix *Index) Root() string {
+	return ix.root
+}
+
+// Add 記事ファイルを索引に追加する
+func (ix *Index) Add(number int, path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	ix.paths[number] = path
+	ix.numbers[path] = number
+}
+
+// Path 記事番号のローカルファイルを返す
+func (ix *Index) Path(number int) (string, bool) {
+	path, ok := ix.paths[number]
+	return path, ok
+}
+
+// Number ローカルファイルの記事番号を返す
+func (ix *Index) Number(path string) (int, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return 0, false
+	}
+	number, ok := ix.numbers[abs]
+	return number, ok
+}
+
+// LinkResolver 索引を使って記事リンクとローカルファイルのリンクを相互に変換する
+func (ix *Index) LinkResolver(team string) *markdown.LinkResolver {
+	return &markdown.LinkResolver{
+		Team:   team,
+		Local:  ix.Path,
+		Number: ix.Number,
+	}
+}
+
+// Links 記事リンクとローカルファイルのリンクを相互に変換する
+// ワークスペースの索引は最初に必要になったときに一度だけ作成し、コマンドの実行中は使い回す
+type Links struct {
+	team string
+	ix   *Index
+	err  error
+}
+
+// NewLinks チームの記事リンクを変換する Links を作成する
+func NewLinks(team string) *Links {
+	return &Links{team: team}
+}
+
+// Team リンクを変換するチーム名
+func (l *Links) Team() string {
+	return l.team
+}
+
+func (l *Links) index() (*Index, error) {
+	if l.ix == nil && l.err == nil {
+		l.ix, l.err = scanRoot()
+	}
+	return l.ix, l.err
+}
+
+// Track 保存・移動した記事ファイルを索引に反映する（索引を作成する前なら何もしない）
+func (l *Links) Track(number int, path string) {
+	if l.ix == nil {
+		return
+	}
+	if old, ok := l.ix.Path(number); ok {
+		delete(l.ix.numbers, old)
+	}
+	l.ix.Add(number, path)
+}
+
+// ToLocal 本文中の記事リンクを、ワークスペース内のローカルファイルへの相対リンクに書き換える
+// dir は本文を書き込むファイルのディレクトリ
+func (l *Links) ToLocal(body, dir string) (string, error) {
+	ix, err := l.index()
+	if err != nil {
+		return body, err
+	}
+	return ix.LinkResolver(l.team).ToLocal(body, dir), nil
+}
+
+// ToRemote 本文中のローカルファイルへのリンクを /posts/N 形式の記事リンクに戻す
+// dir は本文を読み込んだファイルのディレクトリ
+func (l *Links) ToRemote(body, dir string) (string, error) {
+	if !strings.Contains(body, ".md") {
+		return body, nil
+	}
+	ix, err := l.index()
+	if err != nil {
+		return body, err
+	}
+	return ix.LinkResolver(l.team).ToRemote(body, dir), nil
+}
+
+func scanRoot() (*Index, error) {
+	root, err := Root()
+	if err != nil {
+		return nil, err
+	}
+	return Scan(root)
+}
+
+// LocalizeFiles 保存済みのファイルの本文中のリンクをまとめて書き換える
+// 同時にダウンロードした記事同士のリンクも解決できるよう、全ファイルの保存後に呼び出す
+func LocalizeFiles(team string, paths []string) error {
+	ix, err := scanRoot()
+	if err != nil {
+		return err
+	}
+	resolver := ix.LinkResolver(team)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := markdown.Parse(content)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		body := resolver.ToLocal(doc.Body, filepath.Dir(path))
+		if body == doc.Body {
+			continue
+		}
+		doc.Body = body
+		if err := os.WriteFile(path, doc.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
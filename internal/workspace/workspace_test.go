@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shellme/esa-cli/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "12-設計.md"), "---\ntitle: 設計\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, "docs", "設計メモ.md"), "---\ntitle: メモ\nnumber: 34\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, "下書き.md"), "---\ntitle: 下書き\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, ".esa-cli", "56-隠し.md"), "本文\n")
+
+	ix, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		number   int
+		wantPath string
+		wantOK   bool
+	}{
+		{name: "正常系：ファイル名の記事番号", number: 12, wantPath: filepath.Join(root, "12-設計.md"), wantOK: true},
+		{name: "正常系：Front Matterの記事番号", number: 34, wantPath: filepath.Join(root, "docs", "設計メモ.md"), wantOK: true},
+		{name: "エッジケース：隠しディレクトリは対象外", number: 56, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := ix.Path(tt.number)
+			if path != tt.wantPath || ok != tt.wantOK {
+				t.Errorf("Path(%d) = %q, %v, want %q, %v", tt.number, path, ok, tt.wantPath, tt.wantOK)
+			}
+			if ok {
+				if number, _ := ix.Number(path); number != tt.number {
+					t.Errorf("Number(%q) = %d, want %d", path, number, tt.number)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalizeFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, config.ProjectFileName), "team: docs\n")
+	a := filepath.Join(root, "1-a.md")
+	b := filepath.Join(root, "sub", "2-b.md")
+	writeFile(t, a, "---\ntitle: a\nowner: alice\n---\n\n[b](/posts/2)\n")
+	writeFile(t, b, "---\ntitle: b\n---\n\n[a](https://docs.esa.io/posts/1)\n")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(filepath.Join(root, "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LocalizeFiles("docs", []string{a, b}); err != nil {
+		t.Fatalf("LocalizeFiles() error = %v", err)
+	}
+
+	if got, _ := os.ReadFile(a); string(got) != "---\ntitle: a\nowner: alice\n---\n\n[b](sub/2-b.md)\n" {
+		t.Errorf("a = %q", got)
+	}
+	if got, _ := os.ReadFile(b); string(got) != "---\ntitle: b\n---\n\n[a](../1-a.md)\n" {
+		t.Errorf("b = %q", got)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, config.ProjectFileName), "team: docs\n")
+	writeFile(t, filepath.Join(root, "1-a.md"), "---\ntitle: a\n---\n\n本文\n")
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	links := NewLinks("docs")
+	if got, err := links.ToRemote("[a](1-a.md)\n", root); err != nil || got != "[a](/posts/1)\n" {
+		t.Errorf("ToRemote() = %q, %v", got, err)
+	}
+
+	// 索引は作成済みのため、後から保存したファイルは Track するまで使われない
+	moved := filepath.Join(root, "sub", "1-a.md")
+	writeFile(t, moved, "---\ntitle: a\n---\n\n本文\n")
+	writeFile(t, filepath.Join(root, "2-b.md"), "---\ntitle: b\n---\n\n本文\n")
+	if got, _ := links.ToLocal("[b](/posts/2)\n", root); got != "[b](/posts/2)\n" {
+		t.Errorf("ToLocal() before Track = %q", got)
+	}
+
+	links.Track(2, filepath.Join(root, "2-b.md"))
+	links.Track(1, moved)
+	if got, _ := links.ToLocal("[a](/posts/1) [b](/posts/2)\n", root); got != "[a](sub/1-a.md) [b](2-b.md)\n" {
+		t.Errorf("ToLocal() = %q", got)
+	}
+	if got, _ := links.ToRemote("[a](1-a.md)\n", root); got != "[a](1-a.md)\n" {
+		t.Errorf("ToRemote() of moved file = %q", got)
+	}
+}
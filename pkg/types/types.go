@@ -13,6 +13,8 @@ type Post struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	Category  string    `json:"category"`
 	Tags      []string  `json:"tags"`
+
+	RevisionNumber int `json:"revision_number"`
 }
 
 // PostResponse is a struct for API response for getting a post
@@ -37,12 +39,13 @@ type PostRequest struct {
 
 // CreatePostBody is a struct for the body of a post to be created
 type CreatePostBody struct {
-	Name     string   `json:"name,omitempty"`
-	Category string   `json:"category,omitempty"`
-	Tags     []string `json:"tags,omitempty"`
-	BodyMd   string   `json:"body_md,omitempty"`
-	Wip      bool     `json:"wip"`
-	Message  string   `json:"message,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Category       string   `json:"category,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	BodyMd         string   `json:"body_md,omitempty"`
+	Wip            bool     `json:"wip"`
+	Message        string   `json:"message,omitempty"`
+	TemplatePostID int      `json:"template_post_id,omitempty"`
 }
 
 // CreatePostRequest is a struct for API request for creating a post
@@ -56,5 +59,32 @@ type FrontMatter struct {
 	Category        string   `yaml:"category"`
 	Tags            []string `yaml:"tags"`
 	Wip             bool     `yaml:"wip"`
+	Number          int      `yaml:"number,omitempty"`
+	Team            string   `yaml:"team,omitempty"`
 	RemoteUpdatedAt string   `yaml:"remote_updated_at,omitempty"`
 }
+
+// TokenInfo is a struct for the access token information returned by the API
+type TokenInfo struct {
+	ResourceOwnerID  int      `json:"resource_owner_id"`
+	Scope            []string `json:"scope"`
+	ExpiresInSeconds *int     `json:"expires_in_seconds"`
+}
+
+// User is a struct for the authenticated user returned by the API
+type User struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	ScreenName string `json:"screen_name"`
+	Icon       string `json:"icon"`
+	Email      string `json:"email"`
+}
+
+// Team is a struct for a team returned by the API
+type Team struct {
+	Name        string `json:"name"`
+	Privacy     string `json:"privacy"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	URL         string `json:"url"`
+}